@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // ABI registry used to decode proposals
+)
+
+// ListABIsHandler lists the ABIs calldata is decoded with, built-in ones first
+func ListABIsHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(blockchain.ABIs().List())
+}
+
+// AddABIHandler uploads a contract ABI under a name so proposals calling that contract are decoded
+func AddABIHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string          `json:"name"`
+		ABI  json.RawMessage `json:"abi"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := blockchain.ABIs().Add(req.Name, req.ABI); err != nil {
+		errorResponse(w, "Failed to add ABI", err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"name": req.Name})
+}
+
+// DeleteABIHandler removes an uploaded ABI
+func DeleteABIHandler(w http.ResponseWriter, r *http.Request) {
+	if err := blockchain.ABIs().Remove(mux.Vars(r)["name"]); err != nil {
+		errorResponse(w, "Failed to remove ABI", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Nonce manager state
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"     // Encrypted keystore
+)
+
+// keys is the server side keystore, opened by SetupRoutes
+var keys *wallet.Store
+
+// ImportAccountRequest imports a v3 key file (keyJSON + passphrase), stored encrypted with newPassphrase.
+// Raw private keys are never taken over HTTP; walletctl import-key stores them.
+type ImportAccountRequest struct {
+	KeyJSON       json.RawMessage `json:"keyJSON"`
+	Passphrase    string          `json:"passphrase"`
+	NewPassphrase string          `json:"newPassphrase,omitempty"`
+}
+
+// PassphraseRequest is the body of the export, delete and unlock endpoints
+type PassphraseRequest struct {
+	Passphrase    string `json:"passphrase"`
+	NewPassphrase string `json:"newPassphrase,omitempty"` // Export only: passphrase of the exported file
+	Duration      int64  `json:"duration,omitempty"`      // Unlock only: seconds to stay unlocked, 0 until locked again
+}
+
+// accountAddress reads and validates the {address} path variable
+func accountAddress(w http.ResponseWriter, r *http.Request) (common.Address, bool) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		httpError(w, "Invalid address", http.StatusBadRequest)
+		return common.Address{}, false
+	}
+	return common.HexToAddress(address), true
+}
+
+// ListAccountsHandler lists the addresses held in the keystore
+func ListAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	list := keys.List()
+	if list == nil {
+		list = []wallet.Account{} // Encode an empty keystore as [] rather than null
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+// ImportAccountHandler stores an existing key in the keystore
+func ImportAccountHandler(w http.ResponseWriter, r *http.Request) {
+	var req ImportAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.KeyJSON) == 0 {
+		httpError(w, "keyJSON is required", http.StatusBadRequest)
+		return
+	}
+	newPassphrase := req.NewPassphrase
+	if newPassphrase == "" {
+		newPassphrase = req.Passphrase // Keep the file's passphrase unless told otherwise
+	}
+	address, err := keys.Import(req.KeyJSON, req.Passphrase, newPassphrase)
+	if err != nil {
+		errorResponse(w, "Import failed", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"address": address.Hex()})
+}
+
+// ExportAccountHandler returns the v3 key file of an account encrypted with newPassphrase
+func ExportAccountHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := accountAddress(w, r)
+	if !ok {
+		return
+	}
+	var req PassphraseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	keyJSON, err := keys.Export(address, req.Passphrase, req.NewPassphrase)
+	if err != nil {
+		errorResponse(w, "Export failed", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(keyJSON) // The key file is already JSON
+}
+
+// DeleteAccountHandler removes an account from the keystore
+func DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := accountAddress(w, r)
+	if !ok {
+		return
+	}
+	var req PassphraseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := keys.Delete(address, req.Passphrase); err != nil {
+		errorResponse(w, "Delete failed", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlockAccountHandler keeps an account decrypted in memory so it can sign without a passphrase
+func UnlockAccountHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := accountAddress(w, r)
+	if !ok {
+		return
+	}
+	var req PassphraseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := keys.Unlock(address, req.Passphrase, time.Duration(req.Duration)*time.Second); err != nil {
+		errorResponse(w, "Unlock failed", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"address": address.Hex(), "unlocked": true})
+}
+
+// LockAccountHandler drops the decrypted key of an account from memory
+func LockAccountHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := accountAddress(w, r)
+	if !ok {
+		return
+	}
+	if err := keys.Lock(address); err != nil {
+		errorResponse(w, "Lock failed", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"address": address.Hex(), "unlocked": false})
+}
+
+// AccountNoncesHandler shows the nonces handed out for an account and those still in flight
+func AccountNoncesHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := accountAddress(w, r)
+	if !ok {
+		return
+	}
+	state, pending, err := blockchain.AccountNonces(address)
+	if err != nil {
+		errorResponse(w, "Failed to read nonces", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"address":          address.Hex(),
+		"next":             state.Next,
+		"inFlight":         state.InFlight,
+		"nodePendingNonce": pending,
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Deployment jobs
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+)
+
+// deploymentJob is a deployment job as returned by the API, with explorer links when configured
+type deploymentJob struct {
+	blockchain.DeployJob
+	TxURL       string `json:"explorerUrl,omitempty"`
+	ContractURL string `json:"contractExplorerUrl,omitempty"`
+}
+
+func deploymentResponse(job blockchain.DeployJob) deploymentJob {
+	resp := deploymentJob{DeployJob: job}
+	if network, err := config.Get().Active(); err == nil {
+		resp.TxURL = network.TxURL(job.ID)
+		resp.ContractURL = network.AddressURL(job.ContractAddress)
+	}
+	return resp
+}
+
+// GetDeploymentHandler returns the status of a deployment job: pending, mined, verified or failed
+func GetDeploymentHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := blockchain.DeploymentJobs().Get(mux.Vars(r)["id"])
+	if err != nil {
+		errorResponse(w, "", err)
+		return
+	}
+	json.NewEncoder(w).Encode(deploymentResponse(job))
+}
+
+// ListDeploymentsHandler lists all deployment jobs, newest first
+func ListDeploymentsHandler(w http.ResponseWriter, r *http.Request) {
+	list := []deploymentJob{}
+	for _, job := range blockchain.DeploymentJobs().List() {
+		list = append(list, deploymentResponse(job))
+	}
+	json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain"
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+)
+
+// errorBody is the JSON error envelope of every failed request: {"error": {...}}
+type errorBody struct {
+	Code         string `json:"code"` // Stable, for clients to switch on; see errorCodes
+	Message      string `json:"message"`
+	RevertReason string `json:"revertReason,omitempty"` // Decoded reason when the contract reverted
+}
+
+// errorCodes maps the typed errors of the service to a status and code, first match wins
+var errorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{blockchain.ErrNotOwner, http.StatusForbidden, "not_owner"},
+	{blockchain.ErrOnlyWallet, http.StatusForbidden, "only_wallet"},
+	{blockchain.ErrProposalNotFound, http.StatusNotFound, "proposal_not_found"},
+	{bind.ErrNoCode, http.StatusNotFound, "no_contract"},
+	{blockchain.ErrAlreadyConfirmed, http.StatusConflict, "already_confirmed"},
+	{blockchain.ErrNotConfirmed, http.StatusConflict, "not_confirmed"},
+	{blockchain.ErrAlreadyExecuted, http.StatusConflict, "already_executed"},
+	{blockchain.ErrNotEnoughConfirmations, http.StatusConflict, "not_enough_confirmations"},
+	{blockchain.ErrTimelocked, http.StatusConflict, "timelocked"},
+	{blockchain.ErrCallFailed, http.StatusUnprocessableEntity, "call_failed"},
+	{blockchain.ErrInvalidOwner, http.StatusBadRequest, "invalid_owner"},
+	{blockchain.ErrDuplicateOwner, http.StatusBadRequest, "duplicate_owner"},
+	{blockchain.ErrOwnerNotFound, http.StatusBadRequest, "owner_not_found"},
+	{blockchain.ErrInvalidThreshold, http.StatusBadRequest, "invalid_threshold"},
+	{blockchain.ErrUnknownChange, http.StatusBadRequest, "unknown_change"},
+	{blockchain.ErrInvalidTimelock, http.StatusBadRequest, "invalid_timelock"},
+	{blockchain.ErrInvalidCall, http.StatusBadRequest, "invalid_call"},
+	{blockchain.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{blockchain.ErrABINotFound, http.StatusNotFound, "abi_not_found"},
+	{blockchain.ErrBuiltinABI, http.StatusBadRequest, "builtin_abi"},
+	{blockchain.ErrDeploymentNotFound, http.StatusNotFound, "deployment_not_found"},
+	{blockchain.ErrTxNotFound, http.StatusNotFound, "tx_not_found"},
+	{blockchain.ErrNotIndexed, http.StatusNotFound, "not_indexed"},
+	{blockchain.ErrInvalidWebhook, http.StatusBadRequest, "invalid_webhook"},
+	{blockchain.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found"},
+	{blockchain.ErrDeliveryNotFound, http.StatusNotFound, "dead_letter_not_found"},
+	{blockchain.ErrExecutorDisabled, http.StatusConflict, "executor_disabled"},
+	{blockchain.ErrInsufficientFunds, http.StatusUnprocessableEntity, "insufficient_funds"},
+	{blockchain.ErrFeeCapTooLow, http.StatusBadRequest, "fee_cap_too_low"},
+	{signer.ErrUnknownSigner, http.StatusBadRequest, "unknown_signer"},
+	{wallet.ErrLocked, http.StatusLocked, "account_locked"},
+	{wallet.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+	{wallet.ErrWrongPassphrase, http.StatusUnauthorized, "wrong_passphrase"},
+	{wallet.ErrAccountExists, http.StatusConflict, "account_exists"},
+	{wallet.ErrEmptyPassphrase, http.StatusBadRequest, "passphrase_required"},
+	{wallet.ErrHDWalletNotFound, http.StatusNotFound, "hd_wallet_not_found"},
+	{wallet.ErrInvalidMnemonic, http.StatusBadRequest, "invalid_mnemonic"},
+	{wallet.ErrTooManyAccounts, http.StatusBadRequest, "too_many_accounts"},
+}
+
+// errorHints tell the client how to get past an error, by code
+var errorHints = map[string]string{
+	"account_locked":    "unlock it via /wallet/accounts/{address}/unlock",
+	"executor_disabled": "set executor in the config or MULTISIG_EXECUTOR",
+}
+
+// statusCodes are the codes of errors found by the handlers themselves, by status
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          "invalid_request",
+	http.StatusNotFound:            "not_found",
+	http.StatusConflict:            "conflict",
+	http.StatusInternalServerError: "internal_error",
+	http.StatusBadGateway:          "node_unavailable",
+}
+
+// writeError writes the error envelope with status
+func writeError(w http.ResponseWriter, status int, body errorBody) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]errorBody{"error": body})
+}
+
+// httpError reports a problem a handler found itself, like http.Error but as the JSON envelope
+func httpError(w http.ResponseWriter, msg string, status int) {
+	writeError(w, status, errorBody{Code: statusCodes[status], Message: msg})
+}
+
+// errorResponse reports err, prefixed by msg unless it is empty, with the status and code of its type. Unknown reverts
+// are 422 execution_reverted, an unreachable node 502 and anything else 500.
+func errorResponse(w http.ResponseWriter, msg string, err error) {
+	body := errorBody{Code: "internal_error", Message: err.Error()}
+	if msg != "" {
+		body.Message = msg + ": " + body.Message
+	}
+	status := http.StatusInternalServerError
+	var revert *blockchain.RevertError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &revert):
+		body.RevertReason = revert.Reason
+		status, body.Code = http.StatusUnprocessableEntity, "execution_reverted"
+	case errors.As(err, &netErr):
+		status, body.Code = http.StatusBadGateway, "node_unavailable"
+	}
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			status, body.Code = e.status, e.code
+			break
+		}
+	}
+	if hint, ok := errorHints[body.Code]; ok {
+		body.Message += " (" + hint + ")"
+	}
+	writeError(w, status, body)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain"
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+)
+
+// respond runs errorResponse and decodes the envelope it wrote
+func respond(t *testing.T, msg string, err error) (int, errorBody) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	errorResponse(rec, msg, err)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("got content type %q", ct)
+	}
+	var envelope map[string]errorBody
+	if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+		t.Fatal(err)
+	}
+	body, ok := envelope["error"]
+	if !ok {
+		t.Fatalf("no error member in %v", envelope)
+	}
+	return rec.Code, body
+}
+
+func TestErrorResponseCodes(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{blockchain.ErrNotOwner, http.StatusForbidden, "not_owner"},
+		{blockchain.ErrOnlyWallet, http.StatusForbidden, "only_wallet"},
+		{blockchain.ErrProposalNotFound, http.StatusNotFound, "proposal_not_found"},
+		{bind.ErrNoCode, http.StatusNotFound, "no_contract"},
+		{blockchain.ErrAlreadyConfirmed, http.StatusConflict, "already_confirmed"},
+		{blockchain.ErrNotConfirmed, http.StatusConflict, "not_confirmed"},
+		{blockchain.ErrAlreadyExecuted, http.StatusConflict, "already_executed"},
+		{blockchain.ErrNotEnoughConfirmations, http.StatusConflict, "not_enough_confirmations"},
+		{blockchain.ErrTimelocked, http.StatusConflict, "timelocked"},
+		{blockchain.ErrCallFailed, http.StatusUnprocessableEntity, "call_failed"},
+		{blockchain.ErrInvalidOwner, http.StatusBadRequest, "invalid_owner"},
+		{blockchain.ErrInvalidThreshold, http.StatusBadRequest, "invalid_threshold"},
+		{blockchain.ErrInvalidCall, http.StatusBadRequest, "invalid_call"},
+		{blockchain.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+		{blockchain.ErrDeploymentNotFound, http.StatusNotFound, "deployment_not_found"},
+		{blockchain.ErrInsufficientFunds, http.StatusUnprocessableEntity, "insufficient_funds"},
+		{blockchain.ErrFeeCapTooLow, http.StatusBadRequest, "fee_cap_too_low"},
+		{signer.ErrUnknownSigner, http.StatusBadRequest, "unknown_signer"},
+		{wallet.ErrWrongPassphrase, http.StatusUnauthorized, "wrong_passphrase"},
+		{wallet.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+		{wallet.ErrTooManyAccounts, http.StatusBadRequest, "too_many_accounts"},
+		{errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, http.StatusBadGateway, "node_unavailable"},
+	} {
+		t.Run(tc.code, func(t *testing.T) {
+			status, body := respond(t, "Request failed", fmt.Errorf("wrapped: %w", tc.err))
+			if status != tc.status || body.Code != tc.code {
+				t.Fatalf("got %d %s, want %d %s", status, body.Code, tc.status, tc.code)
+			}
+			if !strings.HasPrefix(body.Message, "Request failed: wrapped: ") {
+				t.Fatalf("got message %q", body.Message)
+			}
+		})
+	}
+}
+
+func TestErrorResponseReverts(t *testing.T) {
+	// A known require message takes the code of its typed error and keeps the reason
+	status, body := respond(t, "", &blockchain.RevertError{Reason: "already executed", Err: blockchain.ErrAlreadyExecuted})
+	if status != http.StatusConflict || body.Code != "already_executed" || body.RevertReason != "already executed" {
+		t.Fatalf("got %d %+v, want 409 already_executed with the reason", status, body)
+	}
+	if body.Message != "execution reverted: already executed" {
+		t.Fatalf("got message %q without a prefix", body.Message)
+	}
+
+	// Any other revert is 422
+	status, body = respond(t, "Dry run failed", &blockchain.RevertError{Reason: "arithmetic underflow or overflow", Panic: true})
+	if status != http.StatusUnprocessableEntity || body.Code != "execution_reverted" || body.RevertReason != "arithmetic underflow or overflow" {
+		t.Fatalf("got %d %+v, want 422 execution_reverted with the reason", status, body)
+	}
+}
+
+func TestErrorResponseHints(t *testing.T) {
+	status, body := respond(t, "Signing failed", wallet.ErrLocked)
+	if status != http.StatusLocked || body.Code != "account_locked" || !strings.HasSuffix(body.Message, "("+errorHints["account_locked"]+")") {
+		t.Fatalf("got %d %+v, want 423 account_locked with the unlock hint", status, body)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httpError(rec, "Invalid request body", http.StatusBadRequest)
+	var envelope map[string]errorBody
+	if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || envelope["error"] != (errorBody{Code: "invalid_request", Message: "Invalid request body"}) {
+		t.Fatalf("got %d %+v", rec.Code, envelope)
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Event indexer
+)
+
+// eventNames are the values of ChainEvent.Event, accepted by the ?event= filter
+var eventNames = map[string]bool{
+	"deposit": true, "submitted": true, "confirmed": true, "revoked": true, "executed": true,
+	"ownerAdded": true, "ownerRemoved": true, "requirementChanged": true, "timelockChanged": true,
+}
+
+// ListIndexedWalletsHandler lists the wallets the indexer follows and how far they are indexed
+func ListIndexedWalletsHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(blockchain.EventIndex().List())
+}
+
+// AddIndexedWalletHandler starts indexing a wallet, from its deployment block unless fromBlock is given
+func AddIndexedWalletHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Address   string  `json:"address"`
+		FromBlock *uint64 `json:"fromBlock"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !common.IsHexAddress(req.Address) {
+		httpError(w, "Invalid wallet address", http.StatusBadRequest)
+		return
+	}
+	wallet, err := blockchain.EventIndex().Add(common.HexToAddress(req.Address), req.FromBlock)
+	if err != nil {
+		errorResponse(w, "Failed to index wallet", err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wallet)
+}
+
+// RemoveIndexedWalletHandler stops indexing a wallet and drops its events
+func RemoveIndexedWalletHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	if err := blockchain.EventIndex().Remove(address); err != nil {
+		errorResponse(w, "Failed to remove wallet", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListEventsHandler returns a page of an indexed wallet's events, newest first, with ?event=, ?txIndex=,
+// ?owner=, ?fromBlock=, ?toBlock=, ?page= and ?limit=
+func ListEventsHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	filter := blockchain.EventFilter{Event: query.Get("event"), Owner: query.Get("owner")}
+	if filter.Event != "" && !eventNames[filter.Event] {
+		httpError(w, "Invalid event (deposit, submitted, confirmed, revoked, executed, ownerAdded, ownerRemoved, requirementChanged or timelockChanged)", http.StatusBadRequest)
+		return
+	}
+	if filter.Owner != "" && !common.IsHexAddress(filter.Owner) {
+		httpError(w, "Invalid owner", http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("txIndex"); v != "" {
+		index, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			httpError(w, "Invalid txIndex", http.StatusBadRequest)
+			return
+		}
+		filter.TxIndex = &index
+	}
+	for name, dst := range map[string]*uint64{"fromBlock": &filter.FromBlock, "toBlock": &filter.ToBlock} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				httpError(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+	for name, dst := range map[string]*int{"page": &filter.Page, "limit": &filter.Limit} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				httpError(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+
+	page, err := blockchain.EventIndex().Events(address, filter)
+	if err != nil {
+		errorResponse(w, "Failed to read events", err)
+		return
+	}
+	json.NewEncoder(w).Encode(page)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Auto-executor switches and records
+)
+
+// GetAutoExecuteHandler returns whether ready proposals of a wallet are executed automatically and
+// which proposals the executor executed there
+func GetAutoExecuteHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	json.NewEncoder(w).Encode(blockchain.AutoExecutor().Status(address))
+}
+
+// SetAutoExecuteHandler turns automatic execution of a wallet on or off ({"enabled": true})
+func SetAutoExecuteHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+		httpError(w, "Invalid request body, expected {\"enabled\": true|false}", http.StatusBadRequest)
+		return
+	}
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+
+	executor := blockchain.AutoExecutor()
+	var err error
+	if *req.Enabled {
+		err = executor.Enable(address)
+	} else {
+		err = executor.Disable(address)
+	}
+	if err != nil {
+		errorResponse(w, "Failed to change auto-execution", err)
+		return
+	}
+	json.NewEncoder(w).Encode(executor.Status(address))
+}
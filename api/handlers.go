@@ -7,24 +7,56 @@ import (
 	"github.com/ethereum/go-ethereum/common" // Ethereum address conversion utility
 
 	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Internal blockchain interaction logic
-	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"     // Internal wallet generation logic
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"     // Service configuration (network, explorer)
 
 	"github.com/gorilla/mux"
 )
 
-const (
-	rpcURL        = "https://sepolia.infura.io/v3/0fb5b52beb4d454c965b32ef7dd465a9"    // Sepolia testnet RPC endpoint (via Infura)
-	privateKeyHex = "4b1321d42a47cc5c083a1e6e29e1c4049d43b87de123590d8df55f5fc0ec5ea2" //  wallet private key used for transactions
-)
+// txResponse builds the usual {"txHash": ...} body with the tracker's status URL, adding the explorer
+// link of the active network when one is configured
+func txResponse(txHash string) map[string]string {
+	resp := map[string]string{"txHash": txHash, "statusUrl": "/tx/" + txHash}
+	if network, err := config.Get().Active(); err == nil && network.TxURL(txHash) != "" {
+		resp["explorerUrl"] = network.TxURL(txHash)
+	}
+	return resp
+}
+
+// writeTxResult answers a state-changing request with the simulation of a dry run, or else with
+// txResponse. extra fields, such as the decoded call, are added to either.
+func writeTxResult(w http.ResponseWriter, res blockchain.TxResult, extra map[string]interface{}) {
+	resp := map[string]interface{}{}
+	if res.Simulation != nil {
+		resp["dryRun"], resp["simulation"] = true, res.Simulation
+	} else {
+		for k, v := range txResponse(res.TxHash) {
+			resp[k] = v
+		}
+	}
+	for k, v := range extra {
+		resp[k] = v
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateWalletRequest carries the passphrase the new key is encrypted with
+type CreateWalletRequest struct {
+	Passphrase string `json:"passphrase"`
+}
 
 // CreateWalletHandler creates a new Ethereum wallet
-func CreateWalletHandler(w http.ResponseWriter, r *http.Request) { // This generates wallets address/ public key and private key.
-	key, err := wallet.GenerateKey()
+func CreateWalletHandler(w http.ResponseWriter, r *http.Request) { // This generates a key, keeps it in the keystore and returns its address/public key.
+	var req CreateWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	key, err := keys.Create(req.Passphrase)
 	if err != nil {
-		http.Error(w, "Failed to generate wallet", http.StatusInternalServerError)
+		errorResponse(w, "Failed to generate wallet", err)
 		return
 	}
-	json.NewEncoder(w).Encode(key) // Respond with wallet info in JSON
+	json.NewEncoder(w).Encode(key) // Respond with wallet address and public key only
 }
 
 // GetBalanceHandler returns the balance of an address
@@ -32,7 +64,7 @@ func GetBalanceHandler(w http.ResponseWriter, r *http.Request) {
 	address := mux.Vars(r)["address"] // Extract address from URL path
 	balance, err := blockchain.GetBalance(address)
 	if err != nil {
-		http.Error(w, "Failed to get balance", http.StatusInternalServerError)
+		errorResponse(w, "Failed to get balance", err)
 		return
 	}
 	json.NewEncoder(w).Encode(map[string]string{
@@ -45,23 +77,28 @@ func GetBalanceHandler(w http.ResponseWriter, r *http.Request) {
 func TransferHandler(w http.ResponseWriter, r *http.Request) {
 	var txReq blockchain.TransferRequest                           // Struct to hold request body
 	if err := json.NewDecoder(r.Body).Decode(&txReq); err != nil { // Decode JSON body into txReq
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	s, ok := resolveSigner(w, txReq.Signer)
+	if !ok {
 		return
 	}
 
-	txHash, err := blockchain.SendTransaction(txReq)
+	res, err := blockchain.SendTransaction(txReq, s)
 	if err != nil {
-		http.Error(w, "Transaction failed: "+err.Error(), http.StatusInternalServerError)
+		errorResponse(w, "Transaction failed", err)
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"txHash": txHash}) // Return tx hash
+	writeTxResult(w, res, nil) // Return tx hash
 }
 
 // DeployMultisigHandler deploys a new multisig contract
 func DeployMultisigHandler(w http.ResponseWriter, r *http.Request) {
 	var req blockchain.DeployMultisigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		httpError(w, "Invalid request", http.StatusBadRequest)
 		return
 	}
 
@@ -70,12 +107,23 @@ func DeployMultisigHandler(w http.ResponseWriter, r *http.Request) {
 		owners = append(owners, common.HexToAddress(addr))
 	}
 
-	contractAddress, err := blockchain.DeployMultisigWallet(rpcURL, privateKeyHex, owners, req.RequiredConfirmations)
+	s, ok := resolveSigner(w, req.Signer) // Empty means the configured default signer
+	if !ok {
+		return
+	}
+
+	job, sim, err := blockchain.DeployMultisigWallet(s, owners, req.RequiredConfirmations, req.Confirmations, req.TxOptions)
 	if err != nil {
-		http.Error(w, "Deployment failed: "+err.Error(), http.StatusInternalServerError)
+		errorResponse(w, "Deployment failed", err)
+		return
+	}
+	if sim != nil { // Dry run, no job was started
+		writeTxResult(w, blockchain.TxResult{Simulation: sim}, nil)
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"contractAddress": contractAddress.Hex()}) // Return contract address
+	w.Header().Set("Location", "/wallet/multisig/deployments/"+job.ID) // Poll here until the status is verified or failed
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(deploymentResponse(job))
 }
 
 // SubmitMultisigTxHandler submits a multisig transaction to the contract
@@ -83,37 +131,41 @@ func DeployMultisigHandler(w http.ResponseWriter, r *http.Request) {
 func SubmitMultisigTxHandler(w http.ResponseWriter, r *http.Request) {
 	var txReq blockchain.SubmitMultisigTxRequest
 	if err := json.NewDecoder(r.Body).Decode(&txReq); err != nil { // Decode request body
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		httpError(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	txHash, err := blockchain.SubmitMultisigTransaction(txReq)
+	_, call, err := blockchain.BuildCalldata(txReq.CallSpec) // Reject bad calldata before anything is signed
 	if err != nil {
-		http.Error(w, "Multisig tx failed: "+err.Error(), http.StatusInternalServerError)
+		errorResponse(w, "", err)
+		return
+	}
+
+	s, ok := resolveSigner(w, txReq.Signer)
+	if !ok {
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"txHash": txHash}) // Respond with tx hash
+
+	res, err := blockchain.SubmitMultisigTransaction(txReq, s)
+	if err != nil {
+		errorResponse(w, "Multisig tx failed", err)
+		return
+	}
+	writeTxResult(w, res, map[string]interface{}{"call": call}) // With the decoded call, for review
 }
 
-/*func SubmitMultisigTxHandler(w http.ResponseWriter, r *http.Request) {
-	var txReq blockchain.SubmitMultisigTxRequest
-	if err := json.NewDecoder(r.Body).Decode(&txReq); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-	// Pass the correct arguments to DeployMultisigWallet
-	contractAddress, err := blockchain.DeployMultisigWallet(
-		"https://sepolia.infura.io/v3/0fb5b52beb4d454c965b32ef7dd465a9",    // rpc URL
-		"7791d222a29027e9b88f96f3cb5393061c4de7e964109a59a301e44e1e22fd18", // private key
-		req.Owners, // owners
-		req.RequiredConfirmations,
-	)
-	//txHash, err := blockchain.SubmitMultisigTransaction(txReq)
+// BuildCallHandler encodes a proposal's calldata from raw hex or an ABI, method and arguments and
+// returns it decoded, so it can be reviewed before it is submitted
+func BuildCallHandler(w http.ResponseWriter, r *http.Request) {
+	var spec blockchain.CallSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	_, call, err := blockchain.BuildCalldata(spec)
 	if err != nil {
-		http.Error(w, "Multisig tx failed: "+err.Error(), http.StatusInternalServerError)
+		errorResponse(w, "", err)
 		return
 	}
-	//json.NewEncoder(w).Encode(map[string]string{"txHash": txHash})
-	json.NewEncoder(w).Encode(map[string]string{"contractAddress": contractAddress.Hex()})
+	json.NewEncoder(w).Encode(call)
 }
-*/
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+)
+
+// hdWallets holds the HD wallets, opened by SetupRoutes next to the keystore
+var hdWallets *wallet.HDStore
+
+// CreateHDWalletRequest creates a new mnemonic backed wallet
+type CreateHDWalletRequest struct {
+	Words              int    `json:"words,omitempty"`              // 12 (default), 15, 18, 21 or 24
+	MnemonicPassphrase string `json:"mnemonicPassphrase,omitempty"` // Optional BIP-39 passphrase
+	Passphrase         string `json:"passphrase"`                   // Keystore passphrase for the mnemonic and derived keys
+}
+
+// RecoverHDWalletRequest rebuilds a wallet from its mnemonic. Without count the chain is scanned for
+// the accounts in use (blockchain.HDAccountsInUse).
+type RecoverHDWalletRequest struct {
+	Mnemonic           string `json:"mnemonic"`
+	MnemonicPassphrase string `json:"mnemonicPassphrase,omitempty"`
+	Passphrase         string `json:"passphrase"`
+	Count              uint32 `json:"count,omitempty"` // At most wallet.MaxHDAccounts
+}
+
+// CreateHDWalletHandler generates a mnemonic and derives the first account.
+// The mnemonic is only ever returned here, for the owner's paper backup.
+func CreateHDWalletHandler(w http.ResponseWriter, r *http.Request) {
+	var req CreateHDWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Words == 0 {
+		req.Words = 12
+	}
+	if req.Words%3 != 0 || req.Words < 12 || req.Words > 24 {
+		httpError(w, "words must be 12, 15, 18, 21 or 24", http.StatusBadRequest)
+		return
+	}
+
+	mnemonic, hd, err := hdWallets.Create(req.Words/3*32, req.MnemonicPassphrase, req.Passphrase, 1) // 3 words per 32 bits of entropy
+	if err != nil {
+		errorResponse(w, "Failed to create HD wallet", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":       hd.ID,
+		"mnemonic": mnemonic,
+		"accounts": hd.Accounts,
+	})
+}
+
+// ListHDWalletsHandler lists HD wallets and their derived addresses
+func ListHDWalletsHandler(w http.ResponseWriter, r *http.Request) {
+	list, err := hdWallets.List()
+	if err != nil {
+		errorResponse(w, "Failed to list HD wallets", err)
+		return
+	}
+	resp := []map[string]any{}
+	for _, hd := range list {
+		resp = append(resp, map[string]any{"id": hd.ID, "nextIndex": hd.NextIndex, "accounts": hd.Accounts})
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeriveHDAccountHandler derives the next address of an HD wallet
+func DeriveHDAccountHandler(w http.ResponseWriter, r *http.Request) {
+	var req PassphraseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	key, index, err := hdWallets.DeriveNext(mux.Vars(r)["id"], req.Passphrase)
+	if err != nil {
+		errorResponse(w, "Derivation failed", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"index":     index,
+		"path":      wallet.AccountPath(index),
+		"address":   key.Address,
+		"publicKey": key.PublicKey,
+	})
+}
+
+// RecoverHDWalletHandler restores all accounts of a mnemonic into the keystore
+func RecoverHDWalletHandler(w http.ResponseWriter, r *http.Request) {
+	var req RecoverHDWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := wallet.ValidateMnemonic(req.Mnemonic); err != nil {
+		errorResponse(w, "Recovery failed", err)
+		return
+	}
+
+	if req.Count > wallet.MaxHDAccounts {
+		httpError(w, fmt.Sprintf("count must be at most %d", wallet.MaxHDAccounts), http.StatusBadRequest)
+		return
+	}
+	count := req.Count
+	if count == 0 {
+		var err error
+		if count, err = blockchain.HDAccountsInUse(req.Mnemonic, req.MnemonicPassphrase); err != nil {
+			errorResponse(w, "Account scan failed", err)
+			return
+		}
+	}
+
+	hd, err := hdWallets.Recover(req.Mnemonic, req.MnemonicPassphrase, req.Passphrase, count)
+	if err != nil {
+		errorResponse(w, "Recovery failed", err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"id": hd.ID, "nextIndex": hd.NextIndex, "accounts": hd.Accounts})
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Owner inbox
+)
+
+// PendingApprovalsHandler lists the proposals across all known wallets that wait for the owner's confirmation, oldest first
+func PendingApprovalsHandler(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		httpError(w, "Invalid owner address", http.StatusBadRequest)
+		return
+	}
+	inbox, err := blockchain.PendingApprovals(common.HexToAddress(address))
+	if err != nil {
+		errorResponse(w, "Failed to read pending approvals", err)
+		return
+	}
+	json.NewEncoder(w).Encode(inbox)
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Multisig calls and their preconditions
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+)
+
+// multisigTxRequest reads the {address} and {index} path variables and the optional body (signer and fee
+// options) of a call on one multisig proposal
+func multisigTxRequest(w http.ResponseWriter, r *http.Request) (blockchain.MultisigTxRequest, bool) {
+	var req blockchain.MultisigTxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) { // An empty body uses the default signer
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return req, false
+	}
+	index, err := strconv.ParseUint(mux.Vars(r)["index"], 10, 64)
+	if err != nil {
+		httpError(w, "Invalid transaction index", http.StatusBadRequest)
+		return req, false
+	}
+	req.ContractAddress, req.TxIndex = address.Hex(), index
+	return req, true
+}
+
+// ConfirmMultisigTxHandler confirms a proposal as the signer's (owner) account
+func ConfirmMultisigTxHandler(w http.ResponseWriter, r *http.Request) {
+	multisigCall(w, r, "Confirmation failed", blockchain.ConfirmMultisigTransaction)
+}
+
+// RevokeMultisigTxHandler withdraws the signer's confirmation of a proposal that is not executed yet
+func RevokeMultisigTxHandler(w http.ResponseWriter, r *http.Request) {
+	multisigCall(w, r, "Revocation failed", blockchain.RevokeMultisigConfirmation)
+}
+
+// ExecuteMultisigTxHandler executes a proposal that has enough confirmations
+func ExecuteMultisigTxHandler(w http.ResponseWriter, r *http.Request) {
+	multisigCall(w, r, "Execution failed", blockchain.ExecuteMultisigTransaction)
+}
+
+// multisigCall resolves the signer of a proposal call and sends it with call
+func multisigCall(w http.ResponseWriter, r *http.Request, msg string, call func(blockchain.MultisigTxRequest, signer.Signer) (blockchain.TxResult, error)) {
+	req, ok := multisigTxRequest(w, r)
+	if !ok {
+		return
+	}
+	s, ok := resolveSigner(w, req.Signer)
+	if !ok {
+		return
+	}
+	res, err := call(req, s)
+	if err != nil {
+		errorResponse(w, msg, err)
+		return
+	}
+	writeTxResult(w, res, nil)
+}
+
+// multisigAddress reads and validates the {address} path variable of a multisig wallet
+func multisigAddress(w http.ResponseWriter, r *http.Request) (common.Address, bool) {
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		httpError(w, "Invalid wallet address", http.StatusBadRequest)
+		return common.Address{}, false
+	}
+	return common.HexToAddress(address), true
+}
+
+// GetMultisigHandler returns owners, threshold, balance and proposal count of a multisig wallet
+func GetMultisigHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	info, err := blockchain.GetMultisig(address)
+	if err != nil {
+		errorResponse(w, "Failed to read wallet", err)
+		return
+	}
+	json.NewEncoder(w).Encode(info)
+}
+
+// ListProposalsHandler returns a page of proposals, newest first, with ?status=pending|ready|executed, ?page= and ?limit=
+func ListProposalsHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	filter := blockchain.ProposalFilter{Status: blockchain.ProposalStatus(query.Get("status"))}
+	switch filter.Status {
+	case "", blockchain.ProposalPending, blockchain.ProposalReady, blockchain.ProposalExecuted:
+	default:
+		httpError(w, "Invalid status (pending, ready or executed)", http.StatusBadRequest)
+		return
+	}
+	var err error
+	if v := query.Get("page"); v != "" {
+		if filter.Page, err = strconv.Atoi(v); err != nil {
+			httpError(w, "Invalid page", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			httpError(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := blockchain.ListProposals(address, filter)
+	if err != nil {
+		errorResponse(w, "Failed to read proposals", err)
+		return
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetProposalHandler returns one proposal with its confirmation matrix
+func GetProposalHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	index, err := strconv.ParseUint(mux.Vars(r)["index"], 10, 64)
+	if err != nil {
+		httpError(w, "Invalid transaction index", http.StatusBadRequest)
+		return
+	}
+	proposal, err := blockchain.GetProposal(address, index)
+	if err != nil {
+		errorResponse(w, "Failed to read proposal", err)
+		return
+	}
+	json.NewEncoder(w).Encode(proposal)
+}
+
+// OwnerChangeHandler proposes change to the wallet in the path. The owner comes from the {owner} path
+// variable when the route has one; newOwner, requiredConfirmations, delay, minValue, signer and fees from the body.
+func OwnerChangeHandler(change blockchain.OwnerChange) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req blockchain.OwnerChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			httpError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		address, ok := multisigAddress(w, r)
+		if !ok {
+			return
+		}
+		req.ContractAddress, req.Change = address.Hex(), change
+		if owner, ok := mux.Vars(r)["owner"]; ok {
+			req.Owner = owner
+		}
+
+		s, ok := resolveSigner(w, req.Signer)
+		if !ok {
+			return
+		}
+		res, err := blockchain.ProposeOwnerChange(req, s)
+		if err != nil {
+			errorResponse(w, "Owner change failed", err)
+			return
+		}
+		writeTxResult(w, res, nil)
+	}
+}
@@ -4,18 +4,100 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain"
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+
 	"github.com/gorilla/mux"
 )
 
-// SetupRoutes registers all API routes
-func SetupRoutes(router *mux.Router) {
+// SetupRoutes opens the server side stores from the active configuration and registers all API routes
+func SetupRoutes(router *mux.Router) error {
+	cfg := config.Get()
+	keys = wallet.NewStore(cfg.KeystoreDir())        // Encrypted key files, shared by every handler
+	hdWallets = wallet.NewHDStore(cfg.HDDir(), keys) // Mnemonic backed wallets deriving into the keystore
+
+	var err error
+	if signers, err = signer.FromConfig(cfg, keys); err != nil { // Named signers requests refer to instead of sending keys
+		return err
+	}
+	blockchain.DeploymentJobs().Resume() // Keep following deployments that were in progress at the last shutdown
+	blockchain.Transactions().Resume()   // and transactions that were not final yet
+	blockchain.EventIndex().Start()      // Backfill and follow the events of the indexed wallets
+	blockchain.Webhooks().Start()        // Stream the wallets with webhooks and retry undelivered events
+	if cfg.Executor != "" {
+		executor, err := signers.Resolve(cfg.Executor)
+		if err != nil {
+			return fmt.Errorf("executor: %w", err)
+		}
+		blockchain.AutoExecutor().Start(executor) // Watch the wallets auto-execution is enabled for
+	}
+
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) { // welcome message to make sure its working
 		fmt.Fprintln(w, "Welcome to the Ethereum Multisig Wallet Service!")
 	}).Methods("GET")
 
-	router.HandleFunc("/wallet/create", CreateWalletHandler).Methods("POST")         // POST endpoint to generate a new Ethereum wallet (private/public key + address)
-	router.HandleFunc("/wallet/balance/{address}", GetBalanceHandler).Methods("GET") // GET endpoint to retrieve ETH balance
-	router.HandleFunc("/wallet/transfer", TransferHandler).Methods("POST")           // POST endpoint to transfer
-	router.HandleFunc("/wallet/multisig/deploy", DeployMultisigHandler).Methods("POST")
-	router.HandleFunc("/wallet/multisig/submit", SubmitMultisigTxHandler).Methods("POST") // POST endpoint to submit a transaction
+	router.HandleFunc("/wallet/create", CreateWalletHandler).Methods("POST")                    // POST endpoint to generate a new key in the keystore (returns address + public key)
+	router.HandleFunc("/wallet/balance/{address}", GetBalanceHandler).Methods("GET")            // GET endpoint to retrieve ETH balance
+	router.HandleFunc("/wallet/transfer", TransferHandler).Methods("POST")                      // POST endpoint to transfer
+	router.HandleFunc("/wallet/multisig/deploy", DeployMultisigHandler).Methods("POST")         // Starts a deployment job (202)
+	router.HandleFunc("/wallet/multisig/deployments", ListDeploymentsHandler).Methods("GET")    // All deployment jobs
+	router.HandleFunc("/wallet/multisig/deployments/{id}", GetDeploymentHandler).Methods("GET") // Poll one job by tx hash
+	router.HandleFunc("/wallet/multisig/submit", SubmitMultisigTxHandler).Methods("POST")       // POST endpoint to submit a transaction
+	router.HandleFunc("/wallet/multisig/calldata", BuildCallHandler).Methods("POST")            // Encode and decode calldata for review
+
+	router.HandleFunc("/wallet/multisig/{address}", GetMultisigHandler).Methods("GET")                                     // Owners, threshold, balance
+	router.HandleFunc("/wallet/multisig/{address}/transactions", ListProposalsHandler).Methods("GET")                      // Paginated proposals
+	router.HandleFunc("/wallet/multisig/{address}/transactions/{index}", GetProposalHandler).Methods("GET")                // One proposal and its confirmations
+	router.HandleFunc("/wallet/multisig/{address}/transactions/{index}/confirm", ConfirmMultisigTxHandler).Methods("POST") // Confirm as an owner
+	router.HandleFunc("/wallet/multisig/{address}/transactions/{index}/revoke", RevokeMultisigTxHandler).Methods("POST")   // Withdraw a confirmation
+	router.HandleFunc("/wallet/multisig/{address}/transactions/{index}/execute", ExecuteMultisigTxHandler).Methods("POST") // Execute once confirmed
+
+	router.HandleFunc("/wallet/multisig/{address}/owners", OwnerChangeHandler(blockchain.AddOwner)).Methods("POST")              // Propose adding an owner
+	router.HandleFunc("/wallet/multisig/{address}/owners/{owner}", OwnerChangeHandler(blockchain.RemoveOwner)).Methods("DELETE") // Propose removing an owner
+	router.HandleFunc("/wallet/multisig/{address}/owners/{owner}", OwnerChangeHandler(blockchain.ReplaceOwner)).Methods("PUT")   // Propose replacing an owner
+	router.HandleFunc("/wallet/multisig/{address}/requirement", OwnerChangeHandler(blockchain.ChangeRequirement)).Methods("PUT") // Propose a new threshold
+	router.HandleFunc("/wallet/multisig/{address}/timelock", OwnerChangeHandler(blockchain.ChangeTimelock)).Methods("PUT")       // Propose a new timelock
+
+	router.HandleFunc("/wallet/multisig/{address}/auto-execute", GetAutoExecuteHandler).Methods("GET") // Switch and executed proposals
+	router.HandleFunc("/wallet/multisig/{address}/auto-execute", SetAutoExecuteHandler).Methods("PUT") // Turn auto-execution on or off
+
+	router.HandleFunc("/abis", ListABIsHandler).Methods("GET")            // ABIs proposal calldata is decoded with
+	router.HandleFunc("/abis", AddABIHandler).Methods("POST")             // Upload a contract ABI
+	router.HandleFunc("/abis/{name}", DeleteABIHandler).Methods("DELETE") // Remove an uploaded ABI
+
+	router.HandleFunc("/wallet/multisig/{address}/events", ListEventsHandler).Methods("GET")    // Indexed events, filtered and paginated
+	router.HandleFunc("/index/wallets", ListIndexedWalletsHandler).Methods("GET")               // Wallets the indexer follows
+	router.HandleFunc("/index/wallets", AddIndexedWalletHandler).Methods("POST")                // Index a wallet from its deployment block
+	router.HandleFunc("/index/wallets/{address}", RemoveIndexedWalletHandler).Methods("DELETE") // Stop indexing, drop its events
+
+	router.HandleFunc("/wallet/multisig/{address}/stream", StreamEventsHandler).Methods("GET") // Live events as server-sent events
+
+	router.HandleFunc("/owners/{address}/pending", PendingApprovalsHandler).Methods("GET") // Proposals waiting for an owner across all known wallets
+
+	router.HandleFunc("/webhooks", ListWebhooksHandler).Methods("GET")                               // Registered webhooks, ?wallet= filter
+	router.HandleFunc("/webhooks", AddWebhookHandler).Methods("POST")                                // Register a callback URL for a wallet's events
+	router.HandleFunc("/webhooks/dead-letters", ListDeadLettersHandler).Methods("GET")               // Deliveries that ran out of attempts
+	router.HandleFunc("/webhooks/dead-letters/{id}/replay", ReplayDeadLetterHandler).Methods("POST") // Send a dead letter again
+	router.HandleFunc("/webhooks/{id}", RemoveWebhookHandler).Methods("DELETE")                      // Remove a webhook and its undelivered events
+
+	router.HandleFunc("/tx", ListTxHandler).Methods("GET")       // Tracked transactions, ?account= and ?status= filters
+	router.HandleFunc("/tx/{hash}", GetTxHandler).Methods("GET") // Status of one transaction sent by the service
+
+	router.HandleFunc("/wallet/accounts", ListAccountsHandler).Methods("GET")                    // List keystore accounts
+	router.HandleFunc("/wallet/accounts/import", ImportAccountHandler).Methods("POST")           // Import a v3 key file
+	router.HandleFunc("/wallet/accounts/{address}/export", ExportAccountHandler).Methods("POST") // Export a v3 key file
+	router.HandleFunc("/wallet/accounts/{address}/unlock", UnlockAccountHandler).Methods("POST") // Keep a key decrypted in memory
+	router.HandleFunc("/wallet/accounts/{address}/lock", LockAccountHandler).Methods("POST")     // Drop a decrypted key from memory
+	router.HandleFunc("/wallet/accounts/{address}/nonces", AccountNoncesHandler).Methods("GET")  // Nonces handed out and in flight
+	router.HandleFunc("/wallet/accounts/{address}", DeleteAccountHandler).Methods("DELETE")      // Remove a key file
+
+	router.HandleFunc("/wallet/hd", CreateHDWalletHandler).Methods("POST")              // New mnemonic + account 0
+	router.HandleFunc("/wallet/hd", ListHDWalletsHandler).Methods("GET")                // List HD wallets
+	router.HandleFunc("/wallet/hd/recover", RecoverHDWalletHandler).Methods("POST")     // Restore accounts from a mnemonic
+	router.HandleFunc("/wallet/hd/{id}/derive", DeriveHDAccountHandler).Methods("POST") // Derive the next address
+
+	router.HandleFunc("/signers", ListSignersHandler).Methods("GET") // Configured signers, without secrets
+	return nil
 }
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+)
+
+// signers resolves the "signer" field of requests, built by SetupRoutes from the config
+var signers *signer.Registry
+
+// resolveSigner looks up a signer name or account, writing a 400 response when it is unknown
+func resolveSigner(w http.ResponseWriter, ref string) (signer.Signer, bool) {
+	s, err := signers.Resolve(ref)
+	if err != nil {
+		errorResponse(w, "", err)
+		return nil, false
+	}
+	return s, true
+}
+
+// ListSignersHandler lists the configured signers (names, types and addresses only)
+func ListSignersHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(signers.List())
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Live event stream
+)
+
+// streamKeepalive is how often an idle stream sends a comment, so proxies keep the connection open
+const streamKeepalive = 15 * time.Second
+
+// streamPosition reads where a reconnecting client resumes: the Last-Event-ID header or ?lastEventId=
+// ("block-logIndex", resuming after that event), or ?fromBlock=. It returns nil to start with the next block.
+func streamPosition(r *http.Request) (*blockchain.EventPosition, error) {
+	last := r.Header.Get("Last-Event-ID")
+	if last == "" {
+		last = r.URL.Query().Get("lastEventId")
+	}
+	if last != "" {
+		block, index, ok := strings.Cut(last, "-")
+		number, err := strconv.ParseUint(block, 10, 64)
+		logIndex, err2 := strconv.ParseUint(index, 10, 64)
+		if !ok || err != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid event id %q, want block-logIndex", last)
+		}
+		return &blockchain.EventPosition{BlockNumber: number, LogIndex: uint(logIndex) + 1}, nil
+	}
+	if v := r.URL.Query().Get("fromBlock"); v != "" {
+		number, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fromBlock")
+		}
+		return &blockchain.EventPosition{BlockNumber: number}, nil
+	}
+	return nil, nil
+}
+
+// StreamEventsHandler streams a wallet's deposit, submitted, confirmed, revoked and executed events
+// as server-sent events, each with the id to resume from after a disconnect
+func StreamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	address, ok := multisigAddress(w, r)
+	if !ok {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httpError(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	from, err := streamPosition(r)
+	if err != nil {
+		httpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stream, err := blockchain.StreamEvents(address, from)
+	if err != nil {
+		errorResponse(w, "Failed to stream events", err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case e, open := <-stream.Events():
+			if !open {
+				if err := stream.Err(); err != nil {
+					data, _ := json.Marshal(map[string]errorBody{"error": {Code: "stream_failed", Message: err.Error()}})
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+					flusher.Flush()
+				}
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d-%d\nevent: %s\ndata: %s\n\n", e.BlockNumber, e.LogIndex, e.Event, data)
+			flusher.Flush()
+		case <-keepalive.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
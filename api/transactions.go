@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Transaction tracker
+)
+
+// GetTxHandler returns a tracked transaction with its status and the request that sent it
+func GetTxHandler(w http.ResponseWriter, r *http.Request) {
+	tx, err := blockchain.Transactions().Get(mux.Vars(r)["hash"])
+	if err != nil {
+		errorResponse(w, "", err)
+		return
+	}
+	json.NewEncoder(w).Encode(tx)
+}
+
+// ListTxHandler lists tracked transactions, newest first, optionally filtered by ?account= and ?status=
+func ListTxHandler(w http.ResponseWriter, r *http.Request) {
+	filter := blockchain.TxFilter{Account: r.URL.Query().Get("account"), Status: blockchain.TxStatus(r.URL.Query().Get("status"))}
+	if filter.Account != "" && !common.IsHexAddress(filter.Account) {
+		httpError(w, "Invalid account", http.StatusBadRequest)
+		return
+	}
+	if filter.Status != "" && !validTxStatus(filter.Status) {
+		httpError(w, "Invalid status (pending, mined, failed, dropped or replaced)", http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(blockchain.Transactions().List(filter))
+}
+
+func validTxStatus(status blockchain.TxStatus) bool {
+	for _, s := range blockchain.TxStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
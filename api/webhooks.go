@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain" // Webhook registry
+)
+
+// ListWebhooksHandler lists the registered webhooks without their secrets, ?wallet= for one wallet's
+func ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(blockchain.Webhooks().List(r.URL.Query().Get("wallet")))
+}
+
+// AddWebhookHandler registers a callback URL for a wallet's events and returns it with its signing secret
+func AddWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var req blockchain.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	hook, err := blockchain.Webhooks().Add(req)
+	if err != nil {
+		errorResponse(w, "Failed to add webhook", err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// RemoveWebhookHandler deletes a webhook and its undelivered events
+func RemoveWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	if err := blockchain.Webhooks().Remove(mux.Vars(r)["id"]); err != nil {
+		errorResponse(w, "", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeadLettersHandler lists the deliveries that ran out of attempts, ?webhook= for one webhook's
+func ListDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(blockchain.Webhooks().DeadLetters(r.URL.Query().Get("webhook")))
+}
+
+// ReplayDeadLetterHandler sends a dead letter again with a fresh round of attempts
+func ReplayDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	delivery, err := blockchain.Webhooks().Replay(mux.Vars(r)["id"])
+	if err != nil {
+		errorResponse(w, "", err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
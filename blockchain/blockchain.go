@@ -2,38 +2,76 @@ package blockchain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"regexp"
 	"strings"
 
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"    // Network settings (RPC URLs, chain ID, explorer)
 	"github.com/akarkareddy/ethereum-multisig-wallet/contracts" // Auto-generated Go bindings from the smart contract ABI
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"         // For smart contract interaction
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"    // Signs transactions without exposing where the key lives
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"    // Derives the HD accounts recovery scans
+	"github.com/ethereum/go-ethereum"                           // CallMsg for gas estimation
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"         // Call options for the precondition reads
 	"github.com/ethereum/go-ethereum/common"                    // For Ethereum address conversion
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"    // For key and address generation
 	"github.com/ethereum/go-ethereum/ethclient" // Ethereum client for RPC communication
 )
 
-const RPC_URL = "https://sepolia.infura.io/v3/0fb5b52beb4d454c965b32ef7dd465a9" // with Infura Id
+// Dial connects to the active network from the service configuration. Each configured RPC URL
+// is tried in order, and a node reporting a different chain ID than configured is rejected.
+func Dial() (*ethclient.Client, config.Network, error) {
+	network, err := config.Get().Active()
+	if err != nil {
+		return nil, config.Network{}, err
+	}
+
+	var lastErr error
+	for _, url := range network.RPCURLs {
+		client, err := ethclient.Dial(url) //establishes a connection to an Ethereum node
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		chainID, err := client.ChainID(context.Background())
+		if err != nil {
+			client.Close()
+			lastErr = fmt.Errorf("%s: %w", url, err)
+			continue
+		}
+		if network.ChainID != 0 && chainID.Int64() != network.ChainID {
+			client.Close()
+			return nil, config.Network{}, fmt.Errorf("%s reports chain ID %s, expected %d", url, chainID, network.ChainID)
+		}
+		network.ChainID = chainID.Int64() // Fill in the chain ID when the config left it open
+		return client, network, nil
+	}
+	return nil, config.Network{}, fmt.Errorf("failed to connect to Ethereum client: %w", lastErr)
+}
 
 // DeployMultisigRequest for deploying multisig wallet
 type DeployMultisigRequest struct {
 	Owners                []string `json:"owners"`                // List of wallet owners
 	RequiredConfirmations uint8    `json:"requiredConfirmations"` // Minimum confirmations required to execute a transaction
+	Signer                string   `json:"signer,omitempty"`      // Signer name or account paying for the deployment (default signer if empty)
+	Confirmations         uint64   `json:"confirmations"`         // Blocks to wait before verifying (default: the network's confirmations)
+	TxOptions
 }
 
 // SubmitMultisigTxRequest placeholder structucture for submitting the transaction
 type SubmitMultisigTxRequest struct {
-	// Add fields like contractAddress, to, value, data, senderPrivateKey .
 	ContractAddress string `json:"contractAddress"`
 	To              string `json:"to"`
 	Value           string `json:"value"`
-	SenderKey       string `json:"senderPrivateKey"`
+	Signer          string `json:"signer"` // Signer name or owner account submitting the transaction
+	CallSpec               // Calldata of the proposal, none for a plain ETH transfer
+	TxOptions
 }
 
 // GetBalance returns the ETH balance of an address
 func GetBalance(address string) (string, error) {
-	client, err := ethclient.Dial(RPC_URL) //establishes a connection to an Ethereum node
+	client, _, err := Dial()
 	if err != nil {
 		return "", err
 	}
@@ -51,91 +89,240 @@ func GetBalance(address string) (string, error) {
 
 // TransferRequest struct for transfer
 type TransferRequest struct {
-	PrivateKey string `json:"privateKey"`
-	ToAddress  string `json:"toAddress"`
-	Amount     string `json:"amount"` // in ETH
+	Signer    string `json:"signer"` // Signer name or account sending the ETH
+	ToAddress string `json:"toAddress"`
+	Amount    string `json:"amount"` // in ETH
+	TxOptions
 }
 
-// SendTransaction sends ETH from one wallet to another
-func SendTransaction(req TransferRequest) (string, error) {
-	client, err := ethclient.Dial(RPC_URL) //establishes a connection to an Ethereum node
+// SendTransaction sends ETH from the signer's account to another
+func SendTransaction(req TransferRequest, s signer.Signer) (TxResult, error) {
+	client, network, err := Dial()
 	if err != nil {
-		return "", err
+		return TxResult{}, err
 	}
 	defer client.Close()
 
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(req.PrivateKey, "0x")) //ecdsa key to hexa and removes the 0x prefix coomon in key formt
+	valueWei, err := etherToWei(req.Amount)
 	if err != nil {
-		return "", err
+		return TxResult{}, err
 	}
 
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)            //sender address is derived from the public key associated with the private key.
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress) //Each transaction must have a unique nonce to prevent replay attacks
-	if err != nil {
-		return "", err
-	}
+	toAddress := common.HexToAddress(req.ToAddress)
+	chainID := big.NewInt(network.ChainID)
+
+	// Gas is estimated, so contract recipients (like the multisig's receive(), which emits Deposit) work too
+	return sendTx(client, chainID, s, &toAddress, valueWei, nil, req.TxOptions, "transfer", req)
+}
 
-	value, _ := new(big.Float).SetString(req.Amount) // Rrquired amount is convert into big.float=10^18  to wei
-	valueWei := new(big.Int)
-	value.Mul(value, big.NewFloat(1e18)).Int(valueWei)
+// ErrInvalidAmount rejects an ETH amount that is not a non-negative decimal number
+var ErrInvalidAmount = errors.New("invalid amount")
 
-	gasLimit := uint64(21000)                                   //standard gas limit for simple ETH transfers
-	gasPrice, _ := client.SuggestGasPrice(context.Background()) // can retrive current gas prize for the eth blockchain network.
+// decimalAmount is a plain decimal number: digits with an optional fraction, no sign, exponent or hex
+var decimalAmount = regexp.MustCompile(`^([0-9]+)(?:\.([0-9]+))?$`)
 
-	toAddress := common.HexToAddress(req.ToAddress)
-	tx := types.NewTransaction(nonce, toAddress, valueWei, gasLimit, gasPrice, nil) //A new transaction is created with noce ,toaddress,etc..
+// etherToWei converts a decimal amount of ETH to wei. It is exact, so large values keep every wei;
+// an amount with more than 18 decimals (a fraction of a wei) is rejected rather than truncated.
+func etherToWei(amount string) (*big.Int, error) {
+	m := decimalAmount.FindStringSubmatch(amount)
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q is not a decimal amount of ETH", ErrInvalidAmount, amount)
+	}
+	if len(m[2]) > 18 {
+		return nil, fmt.Errorf("%w: %s has more than 18 decimals, a fraction of a wei", ErrInvalidAmount, amount)
+	}
+	wei, _ := new(big.Int).SetString(m[1]+m[2]+strings.Repeat("0", 18-len(m[2])), 10)
+	return wei, nil
+}
 
-	chainID, _ := client.NetworkID(context.Background())
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey) //transaction is signed with sender's private key and the EIP-155 signer
+// SubmitMultisigTransaction proposes a transaction to the multisig as the signer's (owner) account
+func SubmitMultisigTransaction(req SubmitMultisigTxRequest, s signer.Signer) (TxResult, error) {
+	client, network, err := Dial()
 	if err != nil {
-		return "", err
+		return TxResult{}, err
+	}
+	defer client.Close()
+
+	contractAddress := common.HexToAddress(req.ContractAddress) //Converts the contract address from a string to an Ethereum address
+	to := common.HexToAddress(req.To)                           // value converted to Eth adess(common)
+
+	value, err := etherToWei(req.Value)
+	if err != nil {
+		return TxResult{}, fmt.Errorf("value: %w", err)
 	}
 
-	err = client.SendTransaction(context.Background(), signedTx) //signed transaction is broadcast to the Ethereum network.
+	call, _, err := BuildCalldata(req.CallSpec) // Raw hex or encoded from the target's ABI
 	if err != nil {
-		return "", err
+		return TxResult{}, err
 	}
+	data, err := packMultisig("submitTransaction", to, value, call) // calldata of submitTransaction(to, value, data)
+	if err != nil {
+		return TxResult{}, err
+	}
+	return sendTx(client, big.NewInt(network.ChainID), s, &contractAddress, big.NewInt(0), data, req.TxOptions, "submit", req)
+}
 
-	return signedTx.Hash().Hex(), nil
+// sendTx prices, sizes, signs and broadcasts a transaction from the signer's account: fees for the
+// requested strategy, a gas limit from EstimateGas (or the override), a check that the balance covers
+// value plus the maximum fee, and a nonce from the nonce manager. to is nil for contract creation.
+// The broadcast transaction is handed to the tracker with its purpose and the request that sent it.
+// With opts.DryRun nothing is sent and the result holds the Simulation instead.
+func sendTx(client *ethclient.Client, chainID *big.Int, s signer.Signer, to *common.Address, value *big.Int, data []byte, opts TxOptions, purpose string, request interface{}) (TxResult, error) {
+	signedTx, sim, err := signAndSend(client, chainID, s, to, value, data, opts, purpose, request)
+	if err != nil || sim != nil {
+		return TxResult{Simulation: sim}, err
+	}
+	return TxResult{TxHash: signedTx.Hash().Hex()}, nil
 }
 
-func SubmitMultisigTransaction(req SubmitMultisigTxRequest) (string, error) {
-	client, err := ethclient.Dial(RPC_URL) //establishes a connection to an Ethereum node
+// signAndSend is sendTx returning the signed transaction itself (nil for a dry run)
+func signAndSend(client *ethclient.Client, chainID *big.Int, s signer.Signer, to *common.Address, value *big.Int, data []byte, opts TxOptions, purpose string, request interface{}) (*types.Transaction, *Simulation, error) {
+	ctx := context.Background()
+	from := s.Address()
+	if opts.DryRun {
+		sim, err := simulate(ctx, client, from, to, value, data, opts)
+		return nil, sim, err
+	}
+
+	fees, err := SuggestFees(ctx, client, opts) // EIP-1559 fees for the requested strategy (gas price on legacy chains)
 	if err != nil {
-		return "", err
+		return nil, nil, err
+	}
+	gas, err := EstimateGas(ctx, client, ethereum.CallMsg{From: from, To: to, Value: value, Data: data}, opts.GasLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkBalance(ctx, client, from, value, gas, fees); err != nil {
+		return nil, nil, err
 	}
-	defer client.Close()
 
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(req.SenderKey, "0x")) //private key to ECDSA
+	// Each transaction must have a unique nonce to prevent replay attacks; the nonce manager hands them out per account
+	signedTx, err := sendWithNonce(client, chainID, from, func(nonce uint64) (*types.Transaction, error) {
+		signedTx, err := s.SignTx(fees.newTx(chainID, nonce, to, value, gas, data), chainID) //transaction is signed by the signer, wherever its key lives
+		if err != nil {
+			return nil, err
+		}
+		return signedTx, client.SendTransaction(ctx, signedTx) //signed transaction is broadcast to the Ethereum network.
+	})
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
+	Transactions().track(signedTx, chainID, from, purpose, request)
+	return signedTx, nil, nil
+}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(11155111)) //Creates (auth) object for signing and submitting transactions.
-	if err != nil {                                                                   //chain ID ensures the transaction is valid
-		return "", err
+// packMultisig encodes a call of the multisig contract
+func packMultisig(method string, args ...interface{}) ([]byte, error) {
+	parsed, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		return nil, err
 	}
+	return parsed.Pack(method, args...)
+}
 
-	contractAddress := common.HexToAddress(req.ContractAddress)      //Converts the contract address from a string to an Ethereum address
-	instance, err := contracts.NewContracts(contractAddress, client) // contract binding for interacting with the deployed multisig contract.
+// AccountsUsed reports for each address whether it has ever sent a transaction or holds a balance.
+// HD wallet recovery uses it to find how many derived accounts are in use.
+func AccountsUsed(addresses []common.Address) ([]bool, error) {
+	client, _, err := Dial()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	defer client.Close()
+
+	used := make([]bool, len(addresses))
+	for i, address := range addresses {
+		nonce, err := client.NonceAt(context.Background(), address, nil)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := client.BalanceAt(context.Background(), address, nil)
+		if err != nil {
+			return nil, err
+		}
+		used[i] = nonce > 0 || balance.Sign() > 0
 	}
+	return used, nil
+}
 
-	to := common.HexToAddress(req.To) // value converted to Eth adess(common)
+// hdRecoveryGap is how many consecutive unused accounts end the scan of HDAccountsInUse
+const hdRecoveryGap = 5
 
-	ethFloat, ok := new(big.Float).SetString(req.Value) //transaction of ethereum to float
-	if !ok {
-		return "", fmt.Errorf("invalid value: %s", req.Value)
+// HDAccountsInUse scans the accounts derived from mnemonic until hdRecoveryGap consecutive ones have
+// neither a nonce nor a balance, and returns how many accounts to recover: up to the last used one,
+// and at least one.
+func HDAccountsInUse(mnemonic, mnemonicPassphrase string) (uint32, error) {
+	var count, from uint32
+	for {
+		addresses, err := wallet.DeriveAddresses(mnemonic, mnemonicPassphrase, from, hdRecoveryGap)
+		if err != nil {
+			return 0, err
+		}
+		used, err := AccountsUsed(addresses)
+		if err != nil {
+			return 0, err
+		}
+		found := false
+		for i, u := range used {
+			if u {
+				count = from + uint32(i) + 1
+				found = true
+			}
+		}
+		if !found {
+			break // A whole window of unused accounts: nothing further is in use
+		}
+		from += hdRecoveryGap
 	}
-	weiFloat := new(big.Float).Mul(ethFloat, big.NewFloat(1e18)) //converting Wei to big.converting to int float
-	value := new(big.Int)                                        //
-	weiFloat.Int(value)
+	return max(count, 1), nil
+}
 
-	tx, err := instance.SubmitTransaction(auth, to, value, []byte{}) //submitting the transaction with autha and chain iD ,to and value.
+// MultisigTxRequest identifies a proposal of a multisig wallet and the owner acting on it
+type MultisigTxRequest struct {
+	ContractAddress string `json:"contractAddress"`
+	TxIndex         uint64 `json:"txIndex"`
+	Signer          string `json:"signer"` // Signer name or owner account
+	TxOptions
+}
+
+// ConfirmMultisigTransaction confirms proposal req.TxIndex as the signer's (owner) account
+func ConfirmMultisigTransaction(req MultisigTxRequest, s signer.Signer) (TxResult, error) {
+	return transactMultisig(req, s, "confirmTransaction", "confirm")
+}
+
+// RevokeMultisigConfirmation withdraws the signer's (owner) confirmation of proposal req.TxIndex before it is executed
+func RevokeMultisigConfirmation(req MultisigTxRequest, s signer.Signer) (TxResult, error) {
+	return transactMultisig(req, s, "revokeConfirmation", "revoke")
+}
+
+// ExecuteMultisigTransaction executes proposal req.TxIndex as the signer's (owner) account
+func ExecuteMultisigTransaction(req MultisigTxRequest, s signer.Signer) (TxResult, error) {
+	return transactMultisig(req, s, "executeTransaction", "execute")
+}
+
+// transactMultisig calls method(req.TxIndex) on the multisig of req, signed by s and tracked as purpose.
+// The contract's preconditions are checked first, so a call that would revert is never broadcast. A dry
+// run skips them and simulates the call, so its Simulation shows the contract's own revert reason.
+func transactMultisig(req MultisigTxRequest, s signer.Signer, method, purpose string) (TxResult, error) {
+	client, network, err := Dial()
 	if err != nil {
-		return "", err
+		return TxResult{}, err
 	}
+	defer client.Close()
 
-	return tx.Hash().Hex(), nil //Tx hash convetted to Hex.
+	contractAddress := common.HexToAddress(req.ContractAddress)
+	instance, err := contracts.NewContracts(contractAddress, client)
+	if err != nil {
+		return TxResult{}, err
+	}
+	if !req.DryRun {
+		if err := checkMultisigCall(client, &bind.CallOpts{Context: context.Background()}, instance, s.Address(), req.TxIndex, method); err != nil {
+			return TxResult{}, err
+		}
+	}
+
+	data, err := packMultisig(method, new(big.Int).SetUint64(req.TxIndex))
+	if err != nil {
+		return TxResult{}, err
+	}
+	return sendTx(client, big.NewInt(network.ChainID), s, &contractAddress, big.NewInt(0), data, req.TxOptions, purpose, req)
 }
@@ -0,0 +1,44 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEtherToWei(t *testing.T) {
+	for _, tc := range []struct {
+		amount, wei string // wei "" means rejected
+	}{
+		{"0", "0"},
+		{"1", "1000000000000000000"},
+		{"0.5", "500000000000000000"},
+		{"1.000000000000000001", "1000000000000000001"},
+		{"0.000000000000000001", "1"},
+		{"007.25", "7250000000000000000"},
+		{"123456789012345678901234567890.123456789012345678", "123456789012345678901234567890123456789012345678"},
+		{"0.0000000000000000001", ""}, // 19 decimals, below a wei
+		{"1.1234567890123456789", ""},
+		{"-1", ""},
+		{"+1", ""},
+		{"1/3", ""},
+		{"0x10", ""},
+		{"1e18", ""},
+		{"1E-3", ""},
+		{".5", ""},
+		{"1.", ""},
+		{"1,5", ""},
+		{" 1", ""},
+		{"", ""},
+	} {
+		wei, err := etherToWei(tc.amount)
+		if tc.wei == "" {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("%q: got %v %v, want %v", tc.amount, wei, err, ErrInvalidAmount)
+			}
+			continue
+		}
+		if err != nil || wei.String() != tc.wei {
+			t.Errorf("%q: got %v %v, want %s wei", tc.amount, wei, err, tc.wei)
+		}
+	}
+}
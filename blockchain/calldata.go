@@ -0,0 +1,339 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var ErrInvalidCall = errors.New("invalid call")
+
+// CallSpec describes the calldata of a proposal: either raw hex Data, or the target's ABI with a
+// method (name or signature) and its arguments as JSON values. Numbers may be given as JSON numbers
+// or decimal strings, addresses and bytes as hex strings, tuples as objects or arrays.
+type CallSpec struct {
+	Data   string            `json:"data,omitempty"`
+	ABI    json.RawMessage   `json:"abi,omitempty"`    // ABI JSON of the target contract
+	Method string            `json:"method,omitempty"` // e.g. "transfer" or "transfer(address,uint256)"
+	Args   []json.RawMessage `json:"args,omitempty"`
+}
+
+// DecodedCall is calldata shown back for review
+type DecodedCall struct {
+	Data         string       `json:"data"`
+	Selector     string       `json:"selector,omitempty"`
+	Method       string       `json:"method,omitempty"`
+	Signature    string       `json:"signature,omitempty"`
+	Args         []DecodedArg `json:"args,omitempty"`
+	ABI          string       `json:"abi,omitempty"`          // Registry ABI the call was decoded with
+	Alternatives []string     `json:"alternatives,omitempty"` // Other registry ABIs with the same method
+	Unknown      bool         `json:"unknown,omitempty"`      // No known ABI matches: review the raw data before approving
+}
+
+// DecodedArg is one decoded argument
+type DecodedArg struct {
+	Name  string      `json:"name,omitempty"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// BuildCalldata encodes spec and decodes the result again so the caller can review what will be sent.
+// An empty spec is a plain ETH transfer (no calldata). Raw data is decoded against the ABI given with
+// it, and rejected unless it calls one of its methods, or else with the ABI registry.
+func BuildCalldata(spec CallSpec) ([]byte, *DecodedCall, error) {
+	if spec.Data != "" && spec.Method != "" {
+		return nil, nil, fmt.Errorf("%w: give either data or method and args, not both", ErrInvalidCall)
+	}
+
+	var parsed *abi.ABI
+	if len(spec.ABI) > 0 {
+		a, err := abi.JSON(bytes.NewReader(spec.ABI))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: abi: %v", ErrInvalidCall, err)
+		}
+		parsed = &a
+	}
+
+	if spec.Method == "" {
+		data, err := hexutil.Decode(ensureHexPrefix(spec.Data)) // "" becomes "0x", no calldata
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: data: %v", ErrInvalidCall, err)
+		}
+		if parsed == nil {
+			return data, ABIs().Decode(data), nil
+		}
+		if len(data) == 0 {
+			return data, &DecodedCall{Data: hexutil.Encode(data)}, nil
+		}
+		if len(data) < 4 {
+			return nil, nil, fmt.Errorf("%w: data: %d bytes, shorter than a method selector", ErrInvalidCall, len(data))
+		}
+		method, err := parsed.MethodById(data[:4])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: data: %v", ErrInvalidCall, err)
+		}
+		call, err := decodeCall(method, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, call, nil
+	}
+
+	if parsed == nil {
+		return nil, nil, fmt.Errorf("%w: method %q needs the target's abi", ErrInvalidCall, spec.Method)
+	}
+	method, err := findMethod(parsed, spec.Method)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(spec.Args) != len(method.Inputs) {
+		return nil, nil, fmt.Errorf("%w: %s takes %d arguments, got %d", ErrInvalidCall, method.Sig, len(method.Inputs), len(spec.Args))
+	}
+	args := make([]interface{}, len(method.Inputs))
+	for i, input := range method.Inputs {
+		v, err := jsonArg(input.Type, spec.Args[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: argument %d (%s %s): %v", ErrInvalidCall, i, input.Type, input.Name, err)
+		}
+		args[i] = v
+	}
+	packed, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCall, err)
+	}
+	data := append(append([]byte{}, method.ID...), packed...)
+	call, err := decodeCall(method, data)
+	return data, call, err
+}
+
+// findMethod looks name up as a method name or as a full signature like "transfer(address,uint256)"
+func findMethod(parsed *abi.ABI, name string) (*abi.Method, error) {
+	if m, ok := parsed.Methods[name]; ok {
+		return &m, nil
+	}
+	for _, m := range parsed.Methods {
+		if m.Sig == strings.ReplaceAll(name, " ", "") {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no method %q in the abi", ErrInvalidCall, name)
+}
+
+// decodeCall unpacks data as a call of method
+func decodeCall(method *abi.Method, data []byte) (*DecodedCall, error) {
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: data does not match %s: %v", ErrInvalidCall, method.Sig, err)
+	}
+	call := &DecodedCall{Data: hexutil.Encode(data), Selector: hexutil.Encode(method.ID), Method: method.RawName, Signature: method.Sig}
+	for i, input := range method.Inputs {
+		call.Args = append(call.Args, DecodedArg{Name: input.Name, Type: input.Type.String(), Value: displayValue(input.Type, values[i])})
+	}
+	return call, nil
+}
+
+// jsonArg converts a JSON value into the Go value abi.Pack expects for t
+func jsonArg(t abi.Type, raw json.RawMessage) (interface{}, error) {
+	v, err := jsonValue(t, raw)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func jsonValue(t abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	goType := t.GetType()
+	switch t.T {
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected true or false")
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a string")
+		}
+		return reflect.ValueOf(s), nil
+
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("expected a hex address")
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+
+	case abi.UintTy, abi.IntTy:
+		n, err := jsonInt(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+			return reflect.Value{}, fmt.Errorf("%s out of range for %s", n, t)
+		}
+		if t.T == abi.IntTy {
+			limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+			if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+				return reflect.Value{}, fmt.Errorf("%s out of range for %s", n, t)
+			}
+		}
+		if goType == reflect.TypeOf(new(big.Int)) {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(goType).Elem() // uint8 to uint64, int8 to int64
+		if t.T == abi.UintTy {
+			v.SetUint(n.Uint64())
+		} else {
+			v.SetInt(n.Int64())
+		}
+		return v, nil
+
+	case abi.BytesTy:
+		b, err := jsonBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy, abi.FunctionTy:
+		b, err := jsonBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != goType.Len() {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", goType.Len(), len(b))
+		}
+		v := reflect.New(goType).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected an array")
+		}
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(goType, len(items), len(items))
+		} else {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(items))
+			}
+			v = reflect.New(goType).Elem()
+		}
+		for i, item := range items {
+			elem, err := jsonValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %v", i, err)
+			}
+			v.Index(i).Set(elem)
+		}
+		return v, nil
+
+	case abi.TupleTy:
+		items := make([]json.RawMessage, len(t.TupleElems))
+		var byName map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &byName); err == nil {
+			for i, name := range t.TupleRawNames {
+				item, ok := byName[name]
+				if !ok {
+					return reflect.Value{}, fmt.Errorf("missing field %q", name)
+				}
+				items[i] = item
+			}
+		} else if err := json.Unmarshal(raw, &items); err != nil || len(items) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected an object or an array of %d values", len(t.TupleElems))
+		}
+		v := reflect.New(goType).Elem()
+		for i, elemType := range t.TupleElems {
+			elem, err := jsonValue(*elemType, items[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %q: %v", t.TupleRawNames[i], err)
+			}
+			v.Field(i).Set(elem)
+		}
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+}
+
+// jsonInt reads a JSON number or a decimal (or 0x hex) string
+func jsonInt(raw json.RawMessage) (*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("expected an integer")
+		}
+		s = n.String()
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("expected an integer, got %q", s)
+	}
+	return n, nil
+}
+
+// jsonBytes reads a 0x hex string
+func jsonBytes(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("expected a hex string")
+	}
+	b, err := hexutil.Decode(ensureHexPrefix(s))
+	if err != nil {
+		return nil, fmt.Errorf("expected a hex string: %v", err)
+	}
+	return b, nil
+}
+
+func ensureHexPrefix(s string) string {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return "0x" + s
+	}
+	return s
+}
+
+// displayValue turns an unpacked ABI value of type t into plain JSON: integers as decimal strings,
+// addresses checksummed, byte strings as hex, arrays and tuples recursively
+func displayValue(t abi.Type, v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if n, ok := v.(*big.Int); ok {
+			return n.String()
+		}
+		return fmt.Sprint(v) // int8 to uint64
+	case abi.AddressTy:
+		return v.(common.Address).Hex()
+	case abi.BytesTy:
+		return hexutil.Encode(v.([]byte))
+	case abi.FixedBytesTy, abi.FunctionTy:
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	case abi.SliceTy, abi.ArrayTy:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = displayValue(*t.Elem, rv.Index(i).Interface())
+		}
+		return list
+	case abi.TupleTy:
+		fields := map[string]interface{}{}
+		for i, elem := range t.TupleElems {
+			fields[t.TupleRawNames[i]] = displayValue(*elem, rv.Field(i).Interface())
+		}
+		return fields
+	}
+	return v // bool, string
+}
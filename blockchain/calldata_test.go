@@ -0,0 +1,127 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const testRecipient = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+// testABI has a method for each kind of argument BuildCalldata converts
+const testABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"setLimit","inputs":[{"name":"limit","type":"uint8"},{"name":"delta","type":"int16"},{"name":"enabled","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"store","inputs":[{"name":"key","type":"bytes32"},{"name":"value","type":"bytes"},{"name":"note","type":"string"}],"outputs":[]},
+	{"type":"function","name":"batch","inputs":[{"name":"ids","type":"uint256[]"},{"name":"pair","type":"address[2]"}],"outputs":[]},
+	{"type":"function","name":"order","inputs":[{"name":"o","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"amount","type":"uint256"}]}],"outputs":[]}
+]`
+
+func callSpec(method string, args ...string) CallSpec {
+	spec := CallSpec{ABI: json.RawMessage(testABI), Method: method}
+	for _, a := range args {
+		spec.Args = append(spec.Args, json.RawMessage(a))
+	}
+	return spec
+}
+
+func TestBuildCalldataFromMethod(t *testing.T) {
+	// transfer(0x5aAe..., 1000) as encoded by any ERC-20 tooling
+	const transfer = "0xa9059cbb0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed00000000000000000000000000000000000000000000000000000000000003e8"
+	for _, spec := range []CallSpec{
+		callSpec("transfer", `"`+testRecipient+`"`, `"1000"`),
+		callSpec("transfer", `"`+testRecipient+`"`, `1000`),
+		callSpec("transfer", `"`+testRecipient+`"`, `"0x3e8"`),
+		callSpec("transfer(address, uint256)", `"`+testRecipient+`"`, `1000`),
+	} {
+		data, call, err := BuildCalldata(spec)
+		if err != nil {
+			t.Fatalf("%s %s: %v", spec.Method, spec.Args, err)
+		}
+		if hexutil.Encode(data) != transfer {
+			t.Fatalf("%s %s: got %x", spec.Method, spec.Args, data)
+		}
+		want := []DecodedArg{{Name: "to", Type: "address", Value: testRecipient}, {Name: "amount", Type: "uint256", Value: "1000"}}
+		if call.Method != "transfer" || call.Signature != "transfer(address,uint256)" || call.Selector != "0xa9059cbb" || !reflect.DeepEqual(call.Args, want) {
+			t.Fatalf("decoded %+v", call)
+		}
+	}
+}
+
+func TestBuildCalldataArgumentTypes(t *testing.T) {
+	for _, tc := range []struct {
+		spec CallSpec
+		want []interface{}
+	}{
+		{callSpec("setLimit", `255`, `"-300"`, `true`), []interface{}{"255", "-300", true}},
+		{callSpec("store", `"0x`+strings.Repeat("ab", 32)+`"`, `"0x0102"`, `"hi"`), []interface{}{"0x" + strings.Repeat("ab", 32), "0x0102", "hi"}},
+		{callSpec("batch", `[1, "2"]`, `["`+testRecipient+`", "`+testRecipient+`"]`), []interface{}{[]interface{}{"1", "2"}, []interface{}{testRecipient, testRecipient}}},
+		{callSpec("order", `{"maker": "`+testRecipient+`", "amount": 5}`), []interface{}{map[string]interface{}{"maker": testRecipient, "amount": "5"}}},
+		{callSpec("order", `["`+testRecipient+`", 5]`), []interface{}{map[string]interface{}{"maker": testRecipient, "amount": "5"}}},
+	} {
+		_, call, err := BuildCalldata(tc.spec)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tc.spec.Method, tc.spec.Args, err)
+		}
+		var got []interface{}
+		for _, arg := range call.Args {
+			got = append(got, arg.Value)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: decoded %#v, want %#v", tc.spec.Method, got, tc.want)
+		}
+	}
+}
+
+func TestBuildCalldataRejects(t *testing.T) {
+	for name, spec := range map[string]CallSpec{
+		"bad address":         callSpec("transfer", `"0x1234"`, `1`),
+		"negative uint":       callSpec("transfer", `"`+testRecipient+`"`, `-1`),
+		"fraction":            callSpec("transfer", `"`+testRecipient+`"`, `1.5`),
+		"uint8 overflow":      callSpec("setLimit", `256`, `0`, `true`),
+		"int16 overflow":      callSpec("setLimit", `1`, `-32769`, `true`),
+		"bool as string":      callSpec("setLimit", `1`, `0`, `"true"`),
+		"short bytes32":       callSpec("store", `"0x01"`, `"0x"`, `""`),
+		"bytes not hex":       callSpec("store", `"0x`+strings.Repeat("ab", 32)+`"`, `"zz"`, `""`),
+		"fixed array length":  callSpec("batch", `[]`, `["`+testRecipient+`"]`),
+		"tuple missing field": callSpec("order", `{"maker": "`+testRecipient+`"}`),
+		"too few arguments":   callSpec("transfer", `"`+testRecipient+`"`),
+		"unknown method":      callSpec("mint", `1`),
+		"method without abi":  {Method: "transfer"},
+		"data and method":     {Data: "0xa9059cbb", Method: "transfer", ABI: json.RawMessage(testABI)},
+		"invalid abi":         {Method: "transfer", ABI: json.RawMessage(`{"not": "an abi"}`)},
+		"data not hex":        {Data: "0xzz"},
+		"short data":          {Data: "0xa9059c", ABI: json.RawMessage(testABI)},
+		"data of another abi": {Data: "0xdeadbeef", ABI: json.RawMessage(testABI)},
+		"truncated arguments": {Data: "0xa9059cbb0000", ABI: json.RawMessage(testABI)},
+	} {
+		if _, _, err := BuildCalldata(spec); !errors.Is(err, ErrInvalidCall) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrInvalidCall)
+		}
+	}
+}
+
+// Raw calldata given with an ABI is decoded with it, so it reads the same as a built call
+func TestBuildCalldataFromData(t *testing.T) {
+	built, want, err := BuildCalldata(callSpec("transfer", `"`+testRecipient+`"`, `1000`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, call, err := BuildCalldata(CallSpec{Data: hexutil.Encode(built)[2:], ABI: json.RawMessage(testABI)}) // Prefix optional
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, built) || !reflect.DeepEqual(call, want) {
+		t.Fatalf("decoded %+v, want %+v", call, want)
+	}
+
+	// No data is a plain ETH transfer
+	data, call, err = BuildCalldata(CallSpec{ABI: json.RawMessage(testABI)})
+	if err != nil || len(data) != 0 || call.Method != "" || call.Unknown {
+		t.Fatalf("empty data: got %x %+v %v", data, call, err)
+	}
+}
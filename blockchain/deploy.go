@@ -0,0 +1,337 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// DeployStatus is the state of a deployment job
+type DeployStatus string
+
+const (
+	DeployPending  DeployStatus = "pending"  // Broadcast, no receipt yet
+	DeployMined    DeployStatus = "mined"    // Receipt found, waiting for the confirmation depth
+	DeployVerified DeployStatus = "verified" // Deep enough, code and configuration checked
+	DeployFailed   DeployStatus = "failed"   // Reverted, never mined or not the expected contract
+)
+
+const (
+	deployPollInterval = 2 * time.Second
+	deployTimeout      = 30 * time.Minute // Give up on a deployment that has no receipt by then
+)
+
+var ErrDeploymentNotFound = errors.New("deployment not found")
+
+// DeployJob follows one multisig deployment from broadcast to a verified contract
+type DeployJob struct {
+	ID                    string       `json:"id"` // Deployment transaction hash
+	Status                DeployStatus `json:"status"`
+	ContractAddress       string       `json:"contractAddress"` // Derived from deployer and nonce; trust it only once verified
+	Deployer              string       `json:"deployer"`
+	Owners                []string     `json:"owners"`
+	RequiredConfirmations uint8        `json:"requiredConfirmations"`
+	ChainID               int64        `json:"chainId"`
+	Depth                 uint64       `json:"depth"`                 // Block confirmations to wait for
+	BlockNumber           uint64       `json:"blockNumber,omitempty"` // Block the deployment was mined in
+	Confirmations         uint64       `json:"confirmations"`         // Current block confirmations
+	GasUsed               uint64       `json:"gasUsed,omitempty"`
+	Error                 string       `json:"error,omitempty"`
+	CreatedAt             time.Time    `json:"createdAt"`
+	UpdatedAt             time.Time    `json:"updatedAt"`
+}
+
+// Done reports whether the job reached a final status
+func (j DeployJob) Done() bool {
+	return j.Status == DeployVerified || j.Status == DeployFailed
+}
+
+// Deployments keeps the deployment jobs and their watchers, persisted as a JSON file
+type Deployments struct {
+	path string
+	mu   sync.Mutex
+	jobs map[string]*DeployJob
+	done map[string]chan struct{} // Closed when the job is final
+}
+
+var (
+	deploymentsOnce sync.Once
+	deployments     *Deployments
+)
+
+// DeploymentJobs returns the service wide deployment jobs, persisted in the configured data directory
+func DeploymentJobs() *Deployments {
+	deploymentsOnce.Do(func() {
+		path := config.Get().DeploymentsFile()
+		deployments = &Deployments{path: path, jobs: map[string]*DeployJob{}, done: map[string]chan struct{}{}}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &deployments.jobs)
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("deployments: %v", err)
+		}
+	})
+	return deployments
+}
+
+// Resume restarts the watchers of jobs that were not final when the service stopped. Jobs of
+// other networks are left as they are until the service runs on their network again.
+func (d *Deployments) Resume() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id, job := range d.jobs {
+		if !job.Done() && d.done[id] == nil {
+			d.done[id] = make(chan struct{})
+			go d.watch(id)
+		}
+	}
+}
+
+// Get returns a copy of job id
+func (d *Deployments) Get(id string) (DeployJob, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job, ok := d.jobs[strings.ToLower(id)]
+	if !ok {
+		return DeployJob{}, ErrDeploymentNotFound
+	}
+	return *job, nil
+}
+
+// List returns all jobs, newest first
+func (d *Deployments) List() []DeployJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	list := []DeployJob{}
+	for _, job := range d.jobs {
+		list = append(list, *job)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list
+}
+
+// Wait blocks until job id is final or ctx ends
+func (d *Deployments) Wait(ctx context.Context, id string) (DeployJob, error) {
+	d.mu.Lock()
+	done := d.done[strings.ToLower(id)]
+	d.mu.Unlock()
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return DeployJob{}, ctx.Err()
+		}
+	}
+	return d.Get(id)
+}
+
+// start registers a broadcast deployment, watches it in the background and returns a copy of it
+func (d *Deployments) start(job *DeployJob) DeployJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job.ID = strings.ToLower(job.ID)
+	job.Status = DeployPending
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+	d.jobs[job.ID] = job
+	d.done[job.ID] = make(chan struct{})
+	d.save()
+	go d.watch(job.ID)
+	return *job
+}
+
+// update applies change to job id, persists it and wakes waiters once the job is final
+func (d *Deployments) update(id string, change func(job *DeployJob)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job := d.jobs[id]
+	change(job)
+	job.UpdatedAt = time.Now().UTC()
+	d.save()
+	if job.Done() && d.done[id] != nil {
+		close(d.done[id])
+		delete(d.done, id)
+	}
+}
+
+// release stops waiting for job id without changing it, for a job of a network that is not active
+func (d *Deployments) release(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.done[id] != nil {
+		close(d.done[id])
+		delete(d.done, id)
+	}
+}
+
+// watch polls for the receipt of job id until it is deep enough, then verifies the contract.
+// A receipt that disappears again (reorg) sends the job back to pending.
+func (d *Deployments) watch(id string) {
+	fail := func(msg string) {
+		d.update(id, func(job *DeployJob) { job.Status, job.Error = DeployFailed, msg })
+	}
+
+	ticker := time.NewTicker(deployPollInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		job, _ := d.Get(id)
+		client, network, err := Dial()
+		if err != nil {
+			log.Printf("deployment %s: %v", id, err)
+			continue
+		}
+		if job.ChainID != network.ChainID { // Deployed on another network, its node would never know the transaction
+			client.Close()
+			d.release(id)
+			return
+		}
+		done, msg := d.poll(client, job)
+		client.Close()
+		if msg != "" {
+			fail(msg)
+			return
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// poll checks job once. It returns done when the job reached a final status, or a failure message.
+func (d *Deployments) poll(client *ethclient.Client, job DeployJob) (bool, string) {
+	ctx := context.Background()
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(job.ID))
+	if errors.Is(err, ethereum.NotFound) {
+		if time.Since(job.CreatedAt) > deployTimeout {
+			return false, fmt.Sprintf("no receipt after %s, the transaction was dropped or is underpriced", deployTimeout)
+		}
+		if job.Status == DeployMined {
+			d.update(job.ID, func(job *DeployJob) { job.Status, job.BlockNumber, job.Confirmations = DeployPending, 0, 0 }) // Reorged out
+		}
+		return false, ""
+	}
+	if err != nil {
+		log.Printf("deployment %s: %v", job.ID, err)
+		return false, ""
+	}
+	if receipt.Status != 1 {
+		return false, fmt.Sprintf("deployment reverted in block %d", receipt.BlockNumber)
+	}
+	if receipt.ContractAddress != common.HexToAddress(job.ContractAddress) {
+		return false, fmt.Sprintf("receipt created %s, expected %s", receipt.ContractAddress.Hex(), job.ContractAddress)
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		log.Printf("deployment %s: %v", job.ID, err)
+		return false, ""
+	}
+	var confirmations uint64
+	if head >= receipt.BlockNumber.Uint64() {
+		confirmations = head - receipt.BlockNumber.Uint64() + 1
+	}
+	d.update(job.ID, func(job *DeployJob) {
+		job.Status, job.BlockNumber, job.Confirmations, job.GasUsed = DeployMined, receipt.BlockNumber.Uint64(), confirmations, receipt.GasUsed
+	})
+	if confirmations < job.Depth {
+		return false, ""
+	}
+
+	if err := verifyMultisig(ctx, client, job); err != nil {
+		return false, err.Error()
+	}
+	d.update(job.ID, func(job *DeployJob) { job.Status = DeployVerified })
+	log.Printf("Multisig %s verified after %d confirmations", job.ContractAddress, confirmations)
+	EventIndex().track(receipt.ContractAddress, receipt.BlockNumber.Uint64()) // Index its events from the deployment block on
+	return true, ""
+}
+
+// verifyMultisig checks that the code at the job's address is the MultisigWallet runtime code
+// and that the contract holds the requested owners and threshold
+func verifyMultisig(ctx context.Context, client *ethclient.Client, job DeployJob) error {
+	address := common.HexToAddress(job.ContractAddress)
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	// solc copies the runtime code unchanged into the creation code (the contract has no immutables)
+	if len(code) == 0 || !bytes.Contains(common.FromHex(MultisigWalletBytecode), code) {
+		return fmt.Errorf("code at %s is not the MultisigWallet runtime bytecode", job.ContractAddress)
+	}
+
+	instance, err := contracts.NewContracts(address, client)
+	if err != nil {
+		return err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	owners, err := readOwners(opts, instance)
+	if err != nil {
+		return err
+	}
+	if len(owners) != len(job.Owners) {
+		return fmt.Errorf("contract has %d owners, expected %d", len(owners), len(job.Owners))
+	}
+	for i, owner := range owners {
+		if owner != common.HexToAddress(job.Owners[i]) {
+			return fmt.Errorf("owner %d is %s, expected %s", i, owner.Hex(), job.Owners[i])
+		}
+	}
+	required, err := instance.RequiredConfirmations(opts)
+	if err != nil {
+		return err
+	}
+	if required.Cmp(big.NewInt(int64(job.RequiredConfirmations))) != 0 {
+		return fmt.Errorf("contract requires %s confirmations, expected %d", required, job.RequiredConfirmations)
+	}
+	return nil
+}
+
+// readOwners reads the public owners array; the contract has no length getter, so it reads until the index reverts
+func readOwners(opts *bind.CallOpts, instance *contracts.Contracts) ([]common.Address, error) {
+	var owners []common.Address
+	for i := int64(0); ; i++ {
+		owner, err := instance.Owners(opts, big.NewInt(i))
+		if err != nil {
+			if i > 0 && strings.Contains(err.Error(), "revert") {
+				return owners, nil
+			}
+			return nil, fmt.Errorf("failed to read owners: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+}
+
+// save writes the jobs to the deployments file. Called with d.mu held.
+func (d *Deployments) save() {
+	data, err := json.MarshalIndent(d.jobs, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(d.path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(d.path+".tmp", data, 0600)
+	}
+	if err == nil {
+		err = os.Rename(d.path+".tmp", d.path) // Atomic replace so a crash never leaves half a file
+	}
+	if err != nil {
+		log.Printf("deployments: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package blockchain
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// A job of another chain is not found on the active one, but must not time out there
+func TestResumeKeepsOtherChainJobs(t *testing.T) {
+	newSimChain(t)
+	created := time.Now().UTC().Add(-2 * deployTimeout)
+	local := &DeployJob{ID: common.HexToHash("0x01").Hex(), Status: DeployPending, ChainID: simChainID, CreatedAt: created}
+	other := &DeployJob{ID: common.HexToHash("0x02").Hex(), Status: DeployPending, ChainID: 11155111, CreatedAt: created}
+	d := &Deployments{
+		path: filepath.Join(t.TempDir(), "deployments.json"),
+		jobs: map[string]*DeployJob{local.ID: local, other.ID: other},
+		done: map[string]chan struct{}{},
+	}
+
+	d.Resume()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	job, err := d.Wait(ctx, local.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != DeployFailed {
+		t.Fatalf("job on the active chain is %s, want %s", job.Status, DeployFailed)
+	}
+	if job, err = d.Wait(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != DeployPending || job.Error != "" {
+		t.Fatalf("job of another chain is %s (%s), want it left %s", job.Status, job.Error, DeployPending)
+	}
+}
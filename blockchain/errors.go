@@ -0,0 +1,85 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Reverts of the multisig that have no precondition check of their own
+var (
+	ErrCallFailed = errors.New("the proposal's call reverted")         // require(success, "tx failed") in executeTransaction
+	ErrOnlyWallet = errors.New("only the wallet itself may call this") // Owner management called directly instead of proposed
+)
+
+var panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+// revertErrors maps the require messages of MultisigWallet.sol to typed errors
+var revertErrors = map[string]error{
+	"not owner":                ErrNotOwner,
+	"only wallet":              ErrOnlyWallet,
+	"tx does not exist":        ErrProposalNotFound,
+	"already confirmed":        ErrAlreadyConfirmed,
+	"tx not confirmed":         ErrNotConfirmed,
+	"already executed":         ErrAlreadyExecuted,
+	"not enough confirmations": ErrNotEnoughConfirmations,
+	"tx failed":                ErrCallFailed,
+	"timelocked":               ErrTimelocked,
+	"owners required":          ErrInvalidOwner,
+	"invalid owner":            ErrInvalidOwner,
+	"invalid confirmations":    ErrInvalidThreshold,
+	"too few owners left":      ErrInvalidThreshold,
+}
+
+// RevertError is a call the EVM reverted, with its revert data decoded. It unwraps to the typed error
+// of a known require message, so errors.Is(err, ErrAlreadyExecuted) also matches a revert.
+type RevertError struct {
+	Reason string // Error(string) message or Panic(uint256) description; empty without revert data
+	Panic  bool   // Reason comes from Panic(uint256), an assertion or arithmetic failure
+	Data   string // Raw revert data, hex; undecoded for custom errors
+	Err    error  // Typed error for Reason, nil when the message is not one of the contract's
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	case e.Data != "":
+		return "execution reverted with data " + e.Data
+	}
+	return "execution reverted"
+}
+
+func (e *RevertError) Unwrap() error { return e.Err }
+
+// DecodeRevert returns err as a *RevertError when the node reports a revert, and err unchanged otherwise
+func DecodeRevert(err error) error {
+	var dataErr rpc.DataError
+	if err == nil || !errors.As(err, &dataErr) {
+		return err
+	}
+	encoded, _ := dataErr.ErrorData().(string)
+	if encoded == "" || encoded == "0x" {
+		if !strings.Contains(err.Error(), "revert") { // Another RPC error, such as insufficient funds
+			return err
+		}
+		return &RevertError{}
+	}
+
+	revert := &RevertError{Data: encoded}
+	data, decodeErr := hexutil.Decode(encoded)
+	if decodeErr != nil {
+		return revert
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		revert.Reason = reason
+		revert.Panic = bytes.HasPrefix(data, panicSelector)
+		revert.Err = revertErrors[reason]
+	}
+	return revert
+}
@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// rpcError is an RPC error with data, as the node reports a reverted call
+type rpcError struct {
+	msg  string
+	data interface{}
+}
+
+func (e rpcError) Error() string          { return e.msg }
+func (e rpcError) ErrorData() interface{} { return e.data }
+
+// revertData encodes reason as Error(string) revert data
+func revertData(t *testing.T, reason string) string {
+	t.Helper()
+	stringType, _ := abi.NewType("string", "", nil)
+	args, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(append(crypto.Keccak256([]byte("Error(string)"))[:4], args...))
+}
+
+func TestDecodeRevertError(t *testing.T) {
+	err := DecodeRevert(fmt.Errorf("estimate: %w", rpcError{"execution reverted: already executed", revertData(t, "already executed")}))
+	var revert *RevertError
+	if !errors.As(err, &revert) {
+		t.Fatalf("got %T %v, want a *RevertError", err, err)
+	}
+	if revert.Reason != "already executed" || revert.Panic || !errors.Is(err, ErrAlreadyExecuted) {
+		t.Fatalf("got %+v, want reason %q unwrapping to %v", revert, "already executed", ErrAlreadyExecuted)
+	}
+	if err.Error() != "execution reverted: already executed" {
+		t.Fatalf("got message %q", err)
+	}
+
+	// A message the contract does not have keeps its reason but has no typed error
+	err = DecodeRevert(rpcError{"execution reverted: nope", revertData(t, "nope")})
+	if !errors.As(err, &revert) || revert.Reason != "nope" || revert.Err != nil {
+		t.Fatalf("got %+v, want reason %q without a typed error", err, "nope")
+	}
+}
+
+func TestDecodeRevertPanic(t *testing.T) {
+	data := append(crypto.Keccak256([]byte("Panic(uint256)"))[:4], math.U256Bytes(big.NewInt(0x11))...)
+	err := DecodeRevert(rpcError{"execution reverted", hexutil.Encode(data)})
+	var revert *RevertError
+	if !errors.As(err, &revert) {
+		t.Fatalf("got %T %v, want a *RevertError", err, err)
+	}
+	if !revert.Panic || revert.Reason != "arithmetic underflow or overflow" || revert.Err != nil {
+		t.Fatalf("got %+v, want the arithmetic panic", revert)
+	}
+}
+
+func TestDecodeRevertWithoutReason(t *testing.T) {
+	// A custom error is kept as raw data
+	custom := "0xdeadbeef"
+	var revert *RevertError
+	if err := DecodeRevert(rpcError{"execution reverted", custom}); !errors.As(err, &revert) || revert.Data != custom || revert.Reason != "" {
+		t.Fatalf("got %v, want the raw data %s", err, custom)
+	}
+	if err := DecodeRevert(rpcError{"execution reverted", "0x"}); !errors.As(err, &revert) || revert.Error() != "execution reverted" {
+		t.Fatalf("got %v, want a bare revert", err)
+	}
+
+	// Errors that are no revert pass through
+	funds := rpcError{"insufficient funds for gas * price + value", nil}
+	if err := DecodeRevert(funds); err != error(funds) {
+		t.Fatalf("got %v, want it unchanged", err)
+	}
+	plain := errors.New("connection refused")
+	if err := DecodeRevert(plain); err != plain {
+		t.Fatalf("got %v, want it unchanged", err)
+	}
+	if DecodeRevert(nil) != nil {
+		t.Fatal("nil error decoded to an error")
+	}
+}
+
+// Every require message of the contract decodes to a typed error
+func TestRevertErrorsCoverContract(t *testing.T) {
+	source, err := os.ReadFile("../contracts/MultisigWallet.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := regexp.MustCompile(`require\(.*, "([^"]+)"\);`).FindAllStringSubmatch(string(source), -1)
+	if len(messages) == 0 {
+		t.Fatal("no require messages found in the contract")
+	}
+	for _, m := range messages {
+		reason := m[1]
+		want, ok := revertErrors[reason]
+		if !ok {
+			t.Errorf("require message %q has no typed error", reason)
+			continue
+		}
+		if err := DecodeRevert(rpcError{"execution reverted: " + reason, revertData(t, reason)}); !errors.Is(err, want) {
+			t.Errorf("%q: got %v, want %v", reason, err, want)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ExecutionStatus is where an automatic execution of a proposal is
+type ExecutionStatus string
+
+const (
+	ExecutionTimelocked ExecutionStatus = "timelocked" // Waiting for the proposal's timelock to pass
+	ExecutionRetrying   ExecutionStatus = "retrying"   // Sending failed, another attempt follows
+	ExecutionSent       ExecutionStatus = "sent"       // Execute transaction broadcast, follow it by its hash
+	ExecutionFailed     ExecutionStatus = "failed"     // Gave up after executorAttempts
+)
+
+const (
+	executorAttempts     = 3
+	executorRetryDelay   = 10 * time.Second // Times the attempt number
+	executorPollInterval = 3 * time.Second  // Log polling when the node offers no subscriptions
+	executorReconnect    = 5 * time.Second
+)
+
+var ErrExecutorDisabled = errors.New("no executor account configured")
+
+// Execution records one proposal the auto-executor executed or tried to
+type Execution struct {
+	Wallet    string          `json:"wallet"`
+	Index     uint64          `json:"index"`
+	Status    ExecutionStatus `json:"status"`
+	TxHash    string          `json:"txHash,omitempty"`
+	Attempts  int             `json:"attempts"`
+	Error     string          `json:"error,omitempty"` // Last failure
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// ExecutorStatus is the auto-executor switch of one wallet and what it executed there
+type ExecutorStatus struct {
+	Wallet     string      `json:"wallet"`
+	Enabled    bool        `json:"enabled"`
+	Executor   string      `json:"executor,omitempty"` // Owner account sending the execute transactions
+	Executions []Execution `json:"executions"`         // Newest first
+}
+
+// Executor watches the ConfirmTransaction events of the wallets it is enabled for and executes a
+// proposal with the configured executor account as soon as it has enough confirmations. The switches
+// and executions are persisted as a JSON file.
+type Executor struct {
+	path     string
+	mu       sync.Mutex
+	signer   signer.Signer // Nil until Start
+	state    executorState
+	watchers map[string]context.CancelFunc // By lowercase wallet address
+	busy     map[string]bool               // Executions in progress by executionKey
+}
+
+type executorState struct {
+	Wallets    map[string]bool       `json:"wallets"`    // Switch by lowercase wallet address
+	Executions map[string]*Execution `json:"executions"` // By executionKey
+}
+
+var (
+	executorOnce sync.Once
+	executor     *Executor
+)
+
+// AutoExecutor returns the service wide auto-executor, persisted in the configured data directory
+func AutoExecutor() *Executor {
+	executorOnce.Do(func() {
+		path := config.Get().ExecutorFile()
+		executor = &Executor{
+			path:     path,
+			state:    executorState{Wallets: map[string]bool{}, Executions: map[string]*Execution{}},
+			watchers: map[string]context.CancelFunc{},
+			busy:     map[string]bool{},
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &executor.state)
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("executor: %v", err)
+		}
+	})
+	return executor
+}
+
+// Start sets the account execute transactions are sent from and starts watching the enabled wallets
+func (e *Executor) Start(s signer.Signer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.signer = s
+	for wallet, enabled := range e.state.Wallets {
+		if enabled {
+			e.watch(wallet)
+		}
+	}
+}
+
+// Enable turns automatic execution on for wallet. The executor account must be one of its owners.
+func (e *Executor) Enable(wallet common.Address) error {
+	e.mu.Lock()
+	s := e.signer
+	e.mu.Unlock()
+	if s == nil {
+		return ErrExecutorDisabled
+	}
+
+	client, _, err := Dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	r, err := newMultisigReader(client, wallet)
+	if err != nil {
+		return err
+	}
+	if !containsAddress(r.owners, s.Address()) { // executeTransaction is onlyOwner
+		return fmt.Errorf("%w: executor %s", ErrNotOwner, s.Address().Hex())
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := strings.ToLower(wallet.Hex())
+	e.state.Wallets[key] = true
+	e.watch(key)
+	return e.save()
+}
+
+// Disable turns automatic execution off for wallet. Executions waiting out a timelock or a retry delay stop.
+func (e *Executor) Disable(wallet common.Address) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := strings.ToLower(wallet.Hex())
+	delete(e.state.Wallets, key)
+	if cancel := e.watchers[key]; cancel != nil {
+		cancel()
+		delete(e.watchers, key)
+	}
+	return e.save()
+}
+
+// Status returns the switch of wallet and the executions there, newest first
+func (e *Executor) Status(wallet common.Address) ExecutorStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := strings.ToLower(wallet.Hex())
+	status := ExecutorStatus{Wallet: wallet.Hex(), Enabled: e.state.Wallets[key], Executions: []Execution{}}
+	if e.signer != nil {
+		status.Executor = e.signer.Address().Hex()
+	}
+	for _, ex := range e.state.Executions {
+		if strings.EqualFold(ex.Wallet, wallet.Hex()) {
+			status.Executions = append(status.Executions, *ex)
+		}
+	}
+	sort.Slice(status.Executions, func(i, j int) bool { return status.Executions[i].CreatedAt.After(status.Executions[j].CreatedAt) })
+	return status
+}
+
+// wallets returns the wallets automatic execution is enabled for
+func (e *Executor) wallets() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var wallets []string
+	for wallet, enabled := range e.state.Wallets {
+		if enabled {
+			wallets = append(wallets, wallet)
+		}
+	}
+	return wallets
+}
+
+// watch starts the watcher of wallet unless it runs or no executor is set. Called with e.mu held.
+func (e *Executor) watch(wallet string) {
+	if e.signer == nil || e.watchers[wallet] != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.watchers[wallet] = cancel
+	go func() {
+		for {
+			err := e.follow(ctx, common.HexToAddress(wallet))
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("executor %s: %v, reconnecting", wallet, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(executorReconnect):
+			}
+		}
+	}()
+}
+
+// follow first executes the proposals that became ready while nobody watched, then handles the
+// wallet's ConfirmTransaction events until ctx ends or the connection fails. Events come from a
+// subscription when the node supports one and from polling the logs otherwise; either starts before
+// the sweep, so a confirmation that lands during it is still seen.
+func (e *Executor) follow(ctx context.Context, wallet common.Address) error {
+	client, err := dialEvents()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	instance, err := contracts.NewContracts(wallet, client)
+	if err != nil {
+		return err
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	events := make(chan *contracts.ContractsConfirmTransaction)
+	sub, err := instance.WatchConfirmTransaction(&bind.WatchOpts{Context: ctx}, events, nil, nil)
+	if err != nil {
+		if err := e.sweep(ctx, client, wallet); err != nil {
+			return err
+		}
+		return e.poll(ctx, client, instance, wallet, head+1) // From the block before the sweep on
+	}
+	defer sub.Unsubscribe()
+	if err := e.sweep(ctx, client, wallet); err != nil { // Confirmations meanwhile wait in the subscription
+		return err
+	}
+	for {
+		select {
+		case event := <-events:
+			if !event.Raw.Removed {
+				e.consider(ctx, wallet, event.TxIndex.Uint64())
+			}
+		case err := <-sub.Err():
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// poll reads the wallet's ConfirmTransaction logs from block from onwards every executorPollInterval
+func (e *Executor) poll(ctx context.Context, client *ethclient.Client, instance *contracts.Contracts, wallet common.Address, from uint64) error {
+	ticker := time.NewTicker(executorPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if head < from {
+			continue
+		}
+		it, err := instance.FilterConfirmTransaction(&bind.FilterOpts{Start: from, End: &head, Context: ctx}, nil, nil)
+		if err != nil {
+			return err
+		}
+		for it.Next() {
+			e.consider(ctx, wallet, it.Event.TxIndex.Uint64())
+		}
+		if err := it.Error(); err != nil {
+			return err
+		}
+		from = head + 1
+	}
+}
+
+// sweep considers every proposal of wallet that is not executed yet
+func (e *Executor) sweep(ctx context.Context, client bind.ContractBackend, wallet common.Address) error {
+	r, err := newMultisigReader(client, wallet)
+	if err != nil {
+		return err
+	}
+	count, err := r.instance.GetTransactionCount(r.opts)
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count.Uint64(); i++ {
+		t, err := r.instance.Transactions(r.opts, new(big.Int).SetUint64(i))
+		if err != nil {
+			return err
+		}
+		if !t.Executed && t.Confirmations.Cmp(r.required) >= 0 {
+			e.consider(ctx, wallet, i)
+		}
+	}
+	return nil
+}
+
+// consider starts executing proposal index of wallet unless it is in progress or an execute
+// transaction for it is pending or mined. Whether it is ready is checked when sending. The execution
+// stops with ctx, the wallet's watcher.
+func (e *Executor) consider(ctx context.Context, wallet common.Address, index uint64) {
+	key := executionKey(wallet, index)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.busy[key] {
+		return
+	}
+	if ex := e.state.Executions[key]; ex != nil && ex.Status == ExecutionSent {
+		tx, err := Transactions().Get(ex.TxHash)
+		if err != nil || (tx.Status != TxDropped && tx.Status != TxReplaced && tx.Status != TxFailed) {
+			return
+		}
+	}
+	e.busy[key] = true
+	go e.execute(ctx, wallet, index, key)
+}
+
+// execute sends executeTransaction(index) to wallet, retrying failed sends and waiting out a timelock.
+// A proposal that is executed or no longer has enough confirmations (a revocation) is left alone, and
+// waiting ends when ctx does or automatic execution was turned off for wallet.
+func (e *Executor) execute(ctx context.Context, wallet common.Address, index uint64, key string) {
+	defer func() {
+		e.mu.Lock()
+		delete(e.busy, key)
+		e.mu.Unlock()
+	}()
+	e.mu.Lock()
+	s := e.signer
+	e.mu.Unlock()
+
+	req := MultisigTxRequest{ContractAddress: wallet.Hex(), TxIndex: index, Signer: s.Address().Hex()}
+	for attempt := 1; ; attempt++ {
+		res, err := ExecuteMultisigTransaction(req, s)
+		if errors.Is(err, ErrAlreadyExecuted) || errors.Is(err, ErrNotEnoughConfirmations) {
+			return
+		}
+		var locked *TimelockError
+		if errors.As(err, &locked) { // Not a failed attempt; a block past the time must be mined first
+			e.record(wallet, index, attempt, ExecutionTimelocked, "", err.Error())
+			if !e.wait(ctx, wallet, time.Until(locked.ExecutableAt)+executorPollInterval) {
+				log.Printf("executor: stopped waiting for proposal %d of %s", index, wallet.Hex())
+				return
+			}
+			attempt--
+			continue
+		}
+		if err == nil {
+			log.Printf("executor: executed proposal %d of %s in %s", index, wallet.Hex(), res.TxHash)
+			e.record(wallet, index, attempt, ExecutionSent, res.TxHash, "")
+			return
+		}
+		if attempt >= executorAttempts || errors.Is(err, ErrNotOwner) {
+			log.Printf("executor: proposal %d of %s: %v, giving up", index, wallet.Hex(), err)
+			e.record(wallet, index, attempt, ExecutionFailed, "", err.Error())
+			return
+		}
+		e.record(wallet, index, attempt, ExecutionRetrying, "", err.Error())
+		if !e.wait(ctx, wallet, executorRetryDelay*time.Duration(attempt)) {
+			log.Printf("executor: stopped retrying proposal %d of %s", index, wallet.Hex())
+			return
+		}
+	}
+}
+
+// wait sleeps for d and reports whether automatic execution is still on for wallet, false as soon as ctx ends
+func (e *Executor) wait(ctx context.Context, wallet common.Address, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return ctx.Err() == nil && e.state.Wallets[strings.ToLower(wallet.Hex())]
+}
+
+// record stores the outcome of an attempt to execute proposal index of wallet
+func (e *Executor) record(wallet common.Address, index uint64, attempt int, status ExecutionStatus, txHash, msg string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := executionKey(wallet, index)
+	now := time.Now().UTC()
+	ex := e.state.Executions[key]
+	if ex == nil || attempt == 1 { // A new round after an earlier one failed starts a fresh record
+		ex = &Execution{Wallet: wallet.Hex(), Index: index, CreatedAt: now}
+		e.state.Executions[key] = ex
+	}
+	ex.Status, ex.TxHash, ex.Attempts, ex.Error, ex.UpdatedAt = status, txHash, attempt, msg, now
+	if err := e.save(); err != nil {
+		log.Printf("executor: %v", err)
+	}
+}
+
+func executionKey(wallet common.Address, index uint64) string {
+	return fmt.Sprintf("%s/%d", strings.ToLower(wallet.Hex()), index)
+}
+
+// dialEvents connects to the active network's websocket endpoint when one is configured, else like Dial
+func dialEvents() (*ethclient.Client, error) {
+	network, err := config.Get().Active()
+	if err != nil {
+		return nil, err
+	}
+	if network.WSURL == "" {
+		client, _, err := Dial()
+		return client, err
+	}
+	client, err := ethclient.Dial(network.WSURL)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := client.ChainID(context.Background())
+	if err == nil && network.ChainID != 0 && chainID.Int64() != network.ChainID {
+		err = fmt.Errorf("%s reports chain ID %s, expected %d", network.WSURL, chainID, network.ChainID)
+	}
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// save writes the switches and executions to the executor file. Called with e.mu held.
+func (e *Executor) save() error {
+	if err := os.MkdirAll(filepath.Dir(e.path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(e.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := e.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.path) // Atomic replace so a crash never leaves half a file
+}
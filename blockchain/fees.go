@@ -0,0 +1,160 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// FeeStrategy selects which eth_feeHistory percentile of recent priority fees is bid
+type FeeStrategy string
+
+const (
+	FeeSlow     FeeStrategy = "slow"     // 10th percentile, may wait a few blocks
+	FeeStandard FeeStrategy = "standard" // Median
+	FeeFast     FeeStrategy = "fast"     // 90th percentile
+)
+
+// feePercentiles maps each strategy to the reward percentile requested from eth_feeHistory
+var feePercentiles = map[FeeStrategy]float64{FeeSlow: 10, FeeStandard: 50, FeeFast: 90}
+
+const feeHistoryBlocks = 20 // Blocks of history the priority fee is estimated from
+
+var ErrFeeCapTooLow = errors.New("max fee per gas is below the current base fee")
+
+// TxOptions are the per-request gas and fee settings, embedded in the request types
+type TxOptions struct {
+	FeeStrategy          FeeStrategy `json:"feeStrategy,omitempty"`          // slow, standard (default) or fast
+	MaxFeePerGas         string      `json:"maxFeePerGas,omitempty"`         // Cap in wei; the gas price on legacy chains
+	MaxPriorityFeePerGas string      `json:"maxPriorityFeePerGas,omitempty"` // Tip cap in wei
+	GasLimit             uint64      `json:"gasLimit,omitempty"`             // Use instead of the estimate
+	DryRun               bool        `json:"dryRun,omitempty"`               // Only simulate the transaction, never broadcast it
+}
+
+// Fees are the fee fields of a transaction about to be built
+type Fees struct {
+	Legacy    bool     `json:"legacy"`             // Chain without EIP-1559, GasPrice is used
+	GasPrice  *big.Int `json:"gasPrice,omitempty"` // Legacy only
+	GasTipCap *big.Int `json:"maxPriorityFeePerGas,omitempty"`
+	GasFeeCap *big.Int `json:"maxFeePerGas,omitempty"`
+	BaseFee   *big.Int `json:"baseFee,omitempty"` // Base fee expected for the next block
+}
+
+// SuggestFees prices a transaction for the strategy in opts. On EIP-1559 chains the tip is the
+// strategy's percentile of the priority fees paid over the last blocks and the fee cap is twice the
+// next base fee plus the tip, both limited by the caps in opts. Chains whose blocks carry no base
+// fee get a legacy gas price.
+func SuggestFees(ctx context.Context, client *ethclient.Client, opts TxOptions) (*Fees, error) {
+	percentile, ok := feePercentiles[opts.FeeStrategy]
+	if opts.FeeStrategy == "" {
+		percentile, ok = feePercentiles[FeeStandard], true
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown fee strategy %q (slow, standard or fast)", opts.FeeStrategy)
+	}
+	maxFee, err := parseWei("maxFeePerGas", opts.MaxFeePerGas)
+	if err != nil {
+		return nil, err
+	}
+	maxTip, err := parseWei("maxPriorityFeePerGas", opts.MaxPriorityFeePerGas)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil { // Pre-London chain
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &Fees{Legacy: true, GasPrice: capped(gasPrice, maxFee)}, nil
+	}
+
+	history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{percentile})
+	if err != nil {
+		return nil, fmt.Errorf("eth_feeHistory: %w", err)
+	}
+	baseFee := head.BaseFee
+	if n := len(history.BaseFee); n > 0 {
+		baseFee = history.BaseFee[n-1] // The node's base fee for the block after head
+	}
+
+	var rewards []*big.Int
+	for _, reward := range history.Reward {
+		if len(reward) > 0 && reward[0].Sign() > 0 { // Empty blocks report 0
+			rewards = append(rewards, reward[0])
+		}
+	}
+	var tip *big.Int
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		tip = rewards[len(rewards)/2]
+	} else if tip, err = client.SuggestGasTipCap(ctx); err != nil { // No recent transactions to learn from
+		return nil, err
+	}
+	tip = capped(tip, maxTip)
+
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip) // Survives six full blocks of base fee increases
+	if maxFee != nil {
+		if maxFee.Cmp(baseFee) < 0 {
+			return nil, fmt.Errorf("%w (%s < %s wei)", ErrFeeCapTooLow, maxFee, baseFee)
+		}
+		feeCap = capped(feeCap, maxFee)
+		tip = capped(tip, new(big.Int).Sub(feeCap, baseFee)) // Never promise a tip the cap cannot pay
+	}
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap, BaseFee: baseFee}, nil
+}
+
+// MaxGasPrice is the most a unit of gas can cost under f
+func (f *Fees) MaxGasPrice() *big.Int {
+	if f.Legacy {
+		return f.GasPrice
+	}
+	return f.GasFeeCap
+}
+
+// newTx builds a DynamicFeeTx, or a legacy transaction when f is legacy; to is nil for contract creation
+func (f *Fees) newTx(chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, gas uint64, data []byte) *types.Transaction {
+	if f.Legacy {
+		return types.NewTx(&types.LegacyTx{Nonce: nonce, To: to, Value: value, Gas: gas, GasPrice: f.GasPrice, Data: data})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        to,
+		Value:     value,
+		Gas:       gas,
+		GasTipCap: f.GasTipCap,
+		GasFeeCap: f.GasFeeCap,
+		Data:      data,
+	})
+}
+
+// capped returns the smaller of v and limit (nil limit = no cap)
+func capped(v, limit *big.Int) *big.Int {
+	if limit != nil && v.Cmp(limit) > 0 {
+		return new(big.Int).Set(limit)
+	}
+	return v
+}
+
+// parseWei parses an optional decimal wei amount
+func parseWei(field, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s: %s", field, value)
+	}
+	return v, nil
+}
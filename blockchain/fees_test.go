@@ -0,0 +1,131 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// feeNode answers the fee RPCs SuggestFees makes. Each block of its fee history paid the requested
+// percentile in gwei as priority fee, so the tip shows which percentile was asked for.
+type feeNode struct {
+	baseFee    *big.Int // Of the head; nil for a pre-London chain
+	nextBase   *big.Int // Base fee of the block after the head
+	emptyBlock bool     // Fee history without transactions
+}
+
+func (n *feeNode) GetBlockByNumber(number string, full bool) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100), Difficulty: new(big.Int), BaseFee: n.baseFee}, nil
+}
+
+type feeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+func (n *feeNode) FeeHistory(blocks hexutil.Uint, last string, percentiles []float64) (*feeHistory, error) {
+	h := &feeHistory{OldestBlock: (*hexutil.Big)(big.NewInt(81))}
+	for i := 0; i < int(blocks); i++ {
+		reward := new(big.Int)
+		if !n.emptyBlock {
+			reward = gwei(int64(percentiles[0]))
+		}
+		h.Reward = append(h.Reward, []*hexutil.Big{(*hexutil.Big)(reward)})
+		h.BaseFee = append(h.BaseFee, (*hexutil.Big)(n.baseFee))
+		h.GasUsedRatio = append(h.GasUsedRatio, 0.5)
+	}
+	h.BaseFee = append(h.BaseFee, (*hexutil.Big)(n.nextBase))
+	return h, nil
+}
+
+func (n *feeNode) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(gwei(7))
+}
+
+func (n *feeNode) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(gwei(2))
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei))
+}
+
+func dialFeeNode(t *testing.T, node *feeNode) *ethclient.Client {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestSuggestFees(t *testing.T) {
+	london := &feeNode{baseFee: gwei(10), nextBase: gwei(20)}
+	for _, tc := range []struct {
+		name           string
+		node           *feeNode
+		opts           TxOptions
+		tip, feeCap    *big.Int
+		legacyGasPrice *big.Int
+	}{
+		{name: "slow", node: london, opts: TxOptions{FeeStrategy: FeeSlow}, tip: gwei(10), feeCap: gwei(50)},
+		{name: "standard", node: london, opts: TxOptions{FeeStrategy: FeeStandard}, tip: gwei(50), feeCap: gwei(90)},
+		{name: "default is standard", node: london, tip: gwei(50), feeCap: gwei(90)},
+		{name: "fast", node: london, opts: TxOptions{FeeStrategy: FeeFast}, tip: gwei(90), feeCap: gwei(130)},
+		{name: "tip cap", node: london, opts: TxOptions{MaxPriorityFeePerGas: gwei(5).String()}, tip: gwei(5), feeCap: gwei(45)},
+		{name: "fee cap", node: london, opts: TxOptions{MaxFeePerGas: gwei(100).String()}, tip: gwei(50), feeCap: gwei(90)},
+		// The fee cap leaves 25 gwei above the base fee, less than the 50 gwei tip
+		{name: "tip above the fee cap", node: london, opts: TxOptions{MaxFeePerGas: gwei(45).String()}, tip: gwei(25), feeCap: gwei(45)},
+		{name: "no recent transactions", node: &feeNode{baseFee: gwei(10), nextBase: gwei(20), emptyBlock: true}, tip: gwei(2), feeCap: gwei(42)},
+		{name: "legacy", node: &feeNode{}, legacyGasPrice: gwei(7)},
+		{name: "legacy with a cap", node: &feeNode{}, opts: TxOptions{MaxFeePerGas: gwei(5).String()}, legacyGasPrice: gwei(5)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fees, err := SuggestFees(context.Background(), dialFeeNode(t, tc.node), tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.legacyGasPrice != nil {
+				if !fees.Legacy || fees.GasPrice.Cmp(tc.legacyGasPrice) != 0 {
+					t.Fatalf("got %+v, want a legacy gas price of %s", fees, tc.legacyGasPrice)
+				}
+				return
+			}
+			if fees.Legacy || fees.GasTipCap.Cmp(tc.tip) != 0 || fees.GasFeeCap.Cmp(tc.feeCap) != 0 {
+				t.Fatalf("got tip %s and fee cap %s, want %s and %s", fees.GasTipCap, fees.GasFeeCap, tc.tip, tc.feeCap)
+			}
+			if fees.BaseFee.Cmp(tc.node.nextBase) != 0 {
+				t.Fatalf("got base fee %s, want the next block's %s", fees.BaseFee, tc.node.nextBase)
+			}
+		})
+	}
+}
+
+func TestSuggestFeesRejects(t *testing.T) {
+	client := dialFeeNode(t, &feeNode{baseFee: gwei(10), nextBase: gwei(20)})
+	for _, opts := range []TxOptions{
+		{FeeStrategy: "urgent"},
+		{MaxFeePerGas: "-1"},
+		{MaxFeePerGas: "1.5"},
+		{MaxPriorityFeePerGas: "0x10"},
+	} {
+		if _, err := SuggestFees(context.Background(), client, opts); err == nil {
+			t.Errorf("%+v: no error", opts)
+		}
+	}
+	if _, err := SuggestFees(context.Background(), client, TxOptions{MaxFeePerGas: gwei(15).String()}); !errors.Is(err, ErrFeeCapTooLow) {
+		t.Fatalf("fee cap below the base fee: got %v, want %v", err, ErrFeeCapTooLow)
+	}
+}
@@ -0,0 +1,51 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// EstimateGas returns the gas limit for msg: the node's estimate plus the configured safety
+// margin (gasMargin percent), or override when the request sets one. Plain ETH transfers to an
+// account without code cost exactly 21000 and get no margin.
+func EstimateGas(ctx context.Context, client *ethclient.Client, msg ethereum.CallMsg, override uint64) (uint64, error) {
+	if override > 0 {
+		return override, nil
+	}
+	gas, err := client.EstimateGas(ctx, msg)
+	if err != nil && strings.Contains(err.Error(), "insufficient funds") { // Value alone exceeds the balance
+		return 0, fmt.Errorf("%w: %v", ErrInsufficientFunds, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("gas estimation failed: %w", DecodeRevert(err))
+	}
+	if gas == params.TxGas {
+		return gas, nil
+	}
+	return gas + gas*config.Get().GasMargin/100, nil
+}
+
+// checkBalance makes sure from can pay value plus the most the transaction may cost in fees
+func checkBalance(ctx context.Context, client *ethclient.Client, from common.Address, value *big.Int, gas uint64, fees *Fees) error {
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return err
+	}
+	maxCost := new(big.Int).Mul(new(big.Int).SetUint64(gas), fees.MaxGasPrice())
+	needed := new(big.Int).Add(value, maxCost)
+	if balance.Cmp(needed) < 0 {
+		return fmt.Errorf("%w: %s has %s wei, needs %s (value %s + max fee %s)", ErrInsufficientFunds, from.Hex(), balance, needed, value, maxCost)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// OwnerChange is a change of the owner set, threshold or timelock. The contract only accepts these calls
+// from itself, so they are proposed as multisig transactions to the wallet's own address.
+type OwnerChange string
+
+const (
+	AddOwner          OwnerChange = "addOwner"
+	RemoveOwner       OwnerChange = "removeOwner"
+	ReplaceOwner      OwnerChange = "replaceOwner"
+	ChangeRequirement OwnerChange = "changeRequirement"
+	ChangeTimelock    OwnerChange = "changeTimelock" // minValue only exempts plain ETH transfers; proposals with calldata always wait
+)
+
+// Reasons an owner change is rejected before it is proposed
+var (
+	ErrInvalidOwner     = errors.New("invalid owner address")
+	ErrDuplicateOwner   = errors.New("already an owner")
+	ErrOwnerNotFound    = errors.New("not an owner of the wallet")
+	ErrInvalidThreshold = errors.New("invalid confirmation threshold")
+	ErrUnknownChange    = errors.New("unknown owner change")
+)
+
+// OwnerChangeRequest proposes an owner, threshold or timelock change to a multisig
+type OwnerChangeRequest struct {
+	ContractAddress       string      `json:"contractAddress"`
+	Change                OwnerChange `json:"change"`                          // addOwner, removeOwner, replaceOwner, changeRequirement or changeTimelock
+	Owner                 string      `json:"owner,omitempty"`                 // Owner added, removed or replaced
+	NewOwner              string      `json:"newOwner,omitempty"`              // replaceOwner only
+	RequiredConfirmations uint64      `json:"requiredConfirmations,omitempty"` // changeRequirement only
+	Delay                 uint64      `json:"delay,omitempty"`                 // changeTimelock only: seconds, 0 to turn it off
+	MinValue              string      `json:"minValue,omitempty"`              // changeTimelock only: wei, plain transfers of less skip the delay
+	Signer                string      `json:"signer"`                          // Signer name or owner account proposing the change
+	TxOptions
+}
+
+// EncodeAddOwner encodes the wallet's addOwner(owner) call
+func EncodeAddOwner(owner common.Address) ([]byte, error) {
+	return packMultisig("addOwner", owner)
+}
+
+// EncodeRemoveOwner encodes the wallet's removeOwner(owner) call
+func EncodeRemoveOwner(owner common.Address) ([]byte, error) {
+	return packMultisig("removeOwner", owner)
+}
+
+// EncodeReplaceOwner encodes the wallet's replaceOwner(owner, newOwner) call
+func EncodeReplaceOwner(owner, newOwner common.Address) ([]byte, error) {
+	return packMultisig("replaceOwner", owner, newOwner)
+}
+
+// EncodeChangeRequirement encodes the wallet's changeRequirement(required) call
+func EncodeChangeRequirement(required uint64) ([]byte, error) {
+	return packMultisig("changeRequirement", new(big.Int).SetUint64(required))
+}
+
+// ProposeOwnerChange checks req against the wallet's current owners and threshold and, when the change
+// is valid, submits it as a proposal from the wallet to itself. It still needs the usual confirmations
+// and an execute call to take effect.
+func ProposeOwnerChange(req OwnerChangeRequest, s signer.Signer) (TxResult, error) {
+	client, network, err := Dial()
+	if err != nil {
+		return TxResult{}, err
+	}
+	defer client.Close()
+
+	wallet := common.HexToAddress(req.ContractAddress)
+	r, err := newMultisigReader(client, wallet)
+	if err != nil {
+		return TxResult{}, err
+	}
+	if !containsAddress(r.owners, s.Address()) { // submitTransaction is onlyOwner
+		return TxResult{}, fmt.Errorf("%w: %s", ErrNotOwner, s.Address().Hex())
+	}
+	if req.Change == ChangeTimelock && r.timelock == nil {
+		return TxResult{}, fmt.Errorf("%w: %s was deployed without a timelock", ErrUnknownChange, wallet.Hex())
+	}
+	data, err := ownerChangeCall(req, r.owners, r.required.Uint64())
+	if err != nil {
+		return TxResult{}, err
+	}
+	if err := checkOwnerChange(client, wallet, req.Change, data); err != nil {
+		return TxResult{}, err
+	}
+
+	submit, err := packMultisig("submitTransaction", wallet, big.NewInt(0), data)
+	if err != nil {
+		return TxResult{}, err
+	}
+	return sendTx(client, big.NewInt(network.ChainID), s, &wallet, big.NewInt(0), submit, req.TxOptions, "submit", req)
+}
+
+// ownerChangeCall validates req like the contract will (no duplicate owners, the threshold between 1
+// and the owner count after the change) and encodes the call
+func ownerChangeCall(req OwnerChangeRequest, owners []common.Address, required uint64) ([]byte, error) {
+	owner, err := ownerParam(req.Owner)
+	if err != nil && req.Change != ChangeRequirement && req.Change != ChangeTimelock {
+		return nil, err
+	}
+
+	switch req.Change {
+	case AddOwner:
+		if containsAddress(owners, owner) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateOwner, owner.Hex())
+		}
+		return EncodeAddOwner(owner)
+	case RemoveOwner:
+		if !containsAddress(owners, owner) {
+			return nil, fmt.Errorf("%w: %s", ErrOwnerNotFound, owner.Hex())
+		}
+		if uint64(len(owners)-1) < required {
+			return nil, fmt.Errorf("%w: removing %s leaves %d owners for %d required confirmations, lower the requirement first",
+				ErrInvalidThreshold, owner.Hex(), len(owners)-1, required)
+		}
+		return EncodeRemoveOwner(owner)
+	case ReplaceOwner:
+		if !containsAddress(owners, owner) {
+			return nil, fmt.Errorf("%w: %s", ErrOwnerNotFound, owner.Hex())
+		}
+		newOwner, err := ownerParam(req.NewOwner)
+		if err != nil {
+			return nil, err
+		}
+		if containsAddress(owners, newOwner) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateOwner, newOwner.Hex())
+		}
+		return EncodeReplaceOwner(owner, newOwner)
+	case ChangeRequirement:
+		if req.RequiredConfirmations == 0 || req.RequiredConfirmations > uint64(len(owners)) {
+			return nil, fmt.Errorf("%w: %d with %d owners", ErrInvalidThreshold, req.RequiredConfirmations, len(owners))
+		}
+		return EncodeChangeRequirement(req.RequiredConfirmations)
+	case ChangeTimelock:
+		minValue := big.NewInt(0)
+		if req.MinValue != "" {
+			if _, ok := minValue.SetString(req.MinValue, 10); !ok || minValue.Sign() < 0 {
+				return nil, fmt.Errorf("%w: minValue %q is not an amount of wei", ErrInvalidTimelock, req.MinValue)
+			}
+		}
+		return EncodeChangeTimelock(req.Delay, minValue)
+	}
+	return nil, fmt.Errorf("%w %q (addOwner, removeOwner, replaceOwner, changeRequirement or changeTimelock)", ErrUnknownChange, req.Change)
+}
+
+// checkOwnerChange runs the change as the wallet calling itself, the way the executed proposal will. Wallets
+// deployed before owner management have no such function and revert without data, so the proposal could
+// never be executed.
+func checkOwnerChange(client *ethclient.Client, wallet common.Address, change OwnerChange, data []byte) error {
+	_, err := client.CallContract(context.Background(), ethereum.CallMsg{From: wallet, To: &wallet, Data: data}, nil)
+	if err == nil {
+		return nil
+	}
+	var revert *RevertError
+	if errors.As(DecodeRevert(err), &revert) && revert.Reason == "" && revert.Data == "" {
+		return fmt.Errorf("%w: %s was deployed without %s", ErrUnknownChange, wallet.Hex(), change)
+	}
+	return DecodeRevert(err)
+}
+
+// ownerParam parses an owner address of a request, rejecting malformed and zero addresses
+func ownerParam(address string) (common.Address, error) {
+	if !common.IsHexAddress(address) || common.HexToAddress(address) == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%w: %q", ErrInvalidOwner, address)
+	}
+	return common.HexToAddress(address), nil
+}
+
+func containsAddress(list []common.Address, address common.Address) bool {
+	for _, a := range list {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
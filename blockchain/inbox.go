@@ -0,0 +1,216 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// PendingApproval is a proposal that waits for the confirmation of one owner
+type PendingApproval struct {
+	Wallet string `json:"wallet"`
+	Proposal
+	SubmittedAt    *time.Time `json:"submittedAt,omitempty"` // Block time of the submission, absent when its log is not available
+	SubmittedBlock uint64     `json:"submittedBlock,omitempty"`
+}
+
+// OwnerInbox is what waits for an owner across the wallets the service knows
+type OwnerInbox struct {
+	Owner   string            `json:"owner"`
+	Wallets []string          `json:"wallets"` // Known wallets the account owns
+	Pending []PendingApproval `json:"pending"` // Oldest first
+}
+
+// PendingApprovals walks every known wallet owned by owner and returns the proposals that are not
+// executed and not confirmed by owner, oldest submission first. Known wallets are the ones deployed
+// on the active network, indexed, auto-executed or with webhooks.
+func PendingApprovals(owner common.Address) (OwnerInbox, error) {
+	inbox := OwnerInbox{Owner: owner.Hex(), Wallets: []string{}, Pending: []PendingApproval{}}
+	client, _, err := Dial()
+	if err != nil {
+		return inbox, err
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	times := map[uint64]time.Time{} // Block times by number, shared by the wallets
+	for _, wallet := range knownWallets() {
+		code, err := client.CodeAt(ctx, wallet, nil)
+		if err != nil {
+			return inbox, err
+		}
+		if len(code) == 0 { // Deployed on another chain of the same network, or never mined
+			continue
+		}
+		r, err := newMultisigReader(client, wallet)
+		var revert *RevertError
+		if errors.As(DecodeRevert(err), &revert) { // Not a multisig wallet
+			continue
+		}
+		if err != nil {
+			return inbox, err
+		}
+		if !containsAddress(r.owners, owner) {
+			continue
+		}
+		inbox.Wallets = append(inbox.Wallets, wallet.Hex())
+
+		pending, err := r.pendingFor(owner)
+		if err != nil {
+			return inbox, err
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		if err := submissionTimes(ctx, client, r, wallet, pending, times); err != nil {
+			return inbox, err
+		}
+		inbox.Pending = append(inbox.Pending, pending...)
+	}
+
+	sort.SliceStable(inbox.Pending, func(i, j int) bool {
+		a, b := inbox.Pending[i], inbox.Pending[j]
+		if (a.SubmittedAt == nil) != (b.SubmittedAt == nil) {
+			return a.SubmittedAt != nil // Unknown age last
+		}
+		if a.SubmittedAt != nil && !a.SubmittedAt.Equal(*b.SubmittedAt) {
+			return a.SubmittedAt.Before(*b.SubmittedAt)
+		}
+		if a.Wallet != b.Wallet {
+			return a.Wallet < b.Wallet
+		}
+		return a.Index < b.Index
+	})
+	return inbox, nil
+}
+
+// knownWallets collects the wallets deployed on the active network, indexed, auto-executed or with webhooks
+func knownWallets() []common.Address {
+	seen := map[string]bool{}
+	var wallets []common.Address
+	add := func(address string) {
+		key := strings.ToLower(address)
+		if address == "" || seen[key] || !common.IsHexAddress(address) {
+			return
+		}
+		seen[key] = true
+		wallets = append(wallets, common.HexToAddress(address))
+	}
+
+	chainID := int64(0)
+	if network, err := config.Get().Active(); err == nil {
+		chainID = network.ChainID
+	}
+	for _, job := range DeploymentJobs().List() {
+		if job.Status != DeployFailed && (chainID == 0 || job.ChainID == chainID) {
+			add(job.ContractAddress)
+		}
+	}
+	for _, w := range EventIndex().List() {
+		add(w.Address)
+	}
+	for _, w := range AutoExecutor().wallets() {
+		add(w)
+	}
+	for _, hook := range Webhooks().List("") {
+		add(hook.Wallet)
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].Hex() < wallets[j].Hex() })
+	return wallets
+}
+
+// pendingFor reads the proposals that are not executed and not confirmed by owner
+func (r *multisigReader) pendingFor(owner common.Address) ([]PendingApproval, error) {
+	count, err := r.instance.GetTransactionCount(r.opts)
+	if err != nil {
+		return nil, err
+	}
+	var pending []PendingApproval
+	for i := uint64(0); i < count.Uint64(); i++ {
+		index := new(big.Int).SetUint64(i)
+		t, err := r.instance.Transactions(r.opts, index)
+		if err != nil {
+			return nil, err
+		}
+		if t.Executed {
+			continue
+		}
+		confirmed, err := r.instance.IsConfirmed(r.opts, index, owner)
+		if err != nil {
+			return nil, err
+		}
+		if confirmed {
+			continue
+		}
+		p, err := r.proposal(i) // Decoded calldata and every owner's confirmation
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, PendingApproval{Proposal: p})
+	}
+	return pending, nil
+}
+
+// submissionTimes fills in the wallet and the submission block and time of the pending proposals of wallet,
+// from the index when it is synced and otherwise from the SubmitTransaction logs since the wallet's deployment
+func submissionTimes(ctx context.Context, client *ethclient.Client, r *multisigReader, wallet common.Address, pending []PendingApproval, times map[uint64]time.Time) error {
+	for i := range pending {
+		pending[i].Wallet = wallet.Hex()
+	}
+	if submitted, ok := EventIndex().submissions(wallet); ok {
+		for i := range pending {
+			if e, ok := submitted[pending[i].Index]; ok {
+				at := e.Time
+				pending[i].SubmittedAt, pending[i].SubmittedBlock = &at, e.BlockNumber
+			}
+		}
+		return nil
+	}
+
+	indexRule := make([]*big.Int, len(pending))
+	for i, p := range pending {
+		indexRule[i] = new(big.Int).SetUint64(p.Index)
+	}
+	blocks := map[uint64]uint64{} // Submission block by proposal index
+	err := scanLogs(ctx, client, wallet, func(opts *bind.FilterOpts) error {
+		it, err := r.instance.FilterSubmitTransaction(opts, indexRule, nil)
+		if err != nil {
+			return err
+		}
+		for it.Next() {
+			blocks[it.Event.TxIndex.Uint64()] = it.Event.Raw.BlockNumber
+		}
+		return it.Error()
+	})
+	if errors.Is(err, errNoDeploymentBlock) {
+		return nil // Submission times stay unknown, like on nodes that pruned the logs
+	}
+	if err != nil {
+		return err
+	}
+	for i := range pending {
+		number, ok := blocks[pending[i].Index]
+		if !ok {
+			continue
+		}
+		at, ok := times[number]
+		if !ok {
+			header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return err
+			}
+			at = time.Unix(int64(header.Time), 0).UTC()
+			times[number] = at
+		}
+		pending[i].SubmittedAt, pending[i].SubmittedBlock = &at, number
+	}
+	return nil
+}
@@ -0,0 +1,710 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	indexerChunk        = 2000            // Blocks per eth_getLogs request
+	indexerPollInterval = 3 * time.Second // New head polling when the node offers no subscriptions
+	indexerReconnect    = 5 * time.Second
+	indexerReorgDepth   = 128 // Indexed heads remembered to find where a reorg forked off
+	maxEventLimit       = 100 // Largest page of events
+)
+
+var ErrNotIndexed = errors.New("wallet is not indexed")
+
+// errNoDeploymentBlock is a log read that could not find where to start: the wallet is not indexed
+// and bisecting needs historical state the node does not have
+var errNoDeploymentBlock = errors.New("deployment block unknown")
+
+// ChainEvent is a decoded event of an indexed wallet. Only the fields of its kind are set.
+type ChainEvent struct {
+	Wallet                string    `json:"wallet"`
+	Event                 string    `json:"event"`             // deposit, submitted, confirmed, revoked, executed, ownerAdded, ownerRemoved, requirementChanged or timelockChanged
+	TxIndex               *uint64   `json:"txIndex,omitempty"` // Proposal events
+	Owner                 string    `json:"owner,omitempty"`   // confirmed, revoked, ownerAdded, ownerRemoved
+	From                  string    `json:"from,omitempty"`    // deposit
+	To                    string    `json:"to,omitempty"`      // submitted
+	Value                 string    `json:"value,omitempty"`   // Wei; deposit and submitted
+	Data                  string    `json:"data,omitempty"`    // submitted
+	RequiredConfirmations uint64    `json:"requiredConfirmations,omitempty"`
+	Delay                 uint64    `json:"delay,omitempty"`    // timelockChanged
+	MinValue              string    `json:"minValue,omitempty"` // timelockChanged
+	BlockNumber           uint64    `json:"blockNumber"`
+	BlockHash             string    `json:"blockHash"`
+	Time                  time.Time `json:"time"` // Block timestamp
+	TxHash                string    `json:"txHash"`
+	LogIndex              uint      `json:"logIndex"`
+	Removed               bool      `json:"removed,omitempty"` // Streams only: the block was reorged out
+}
+
+// IndexedWallet is a wallet the indexer follows
+type IndexedWallet struct {
+	Address   string `json:"address"`
+	FromBlock uint64 `json:"fromBlock"` // Deployment block, where the backfill started
+	NextBlock uint64 `json:"nextBlock"` // First block not indexed yet
+	Events    int    `json:"events"`
+	Synced    bool   `json:"synced"` // Indexed up to the latest head the indexer saw
+}
+
+// EventFilter selects a page of a wallet's events, newest first. Page starts at 1.
+type EventFilter struct {
+	Event     string  // Empty for all
+	TxIndex   *uint64 // Events of one proposal
+	Owner     string
+	FromBlock uint64
+	ToBlock   uint64 // 0 for no limit
+	Page      int
+	Limit     int
+}
+
+// EventPage is one page of events and the number of events matching the filter
+type EventPage struct {
+	Total  int          `json:"total"`
+	Page   int          `json:"page"`
+	Limit  int          `json:"limit"`
+	Events []ChainEvent `json:"events"`
+}
+
+// Indexer backfills the events of the wallets it follows from their deployment block in chunks of
+// indexerChunk blocks, then follows new heads. Events from blocks a reorg orphaned are rolled back.
+// Wallets, events and the recent heads are persisted as a JSON file per chain, written once a sync
+// stored events or rolled them back. Blocks without events indexed since are read again after a restart.
+type Indexer struct {
+	path    string
+	mu      sync.Mutex
+	state   indexerState
+	dirty   bool // Events changed since the last save
+	started bool
+	wake    chan struct{} // Sync now, a wallet was added
+}
+
+type indexerState struct {
+	Wallets map[string]*walletCursor `json:"wallets"` // By lowercase wallet address
+	Events  []ChainEvent             `json:"events"`  // By block and log index within each wallet
+	Heads   []blockRef               `json:"heads"`   // Last indexed heads, oldest first
+}
+
+// walletCursor is how far a wallet is indexed
+type walletCursor struct {
+	Address   string `json:"address"`
+	FromBlock uint64 `json:"fromBlock"`
+	NextBlock uint64 `json:"nextBlock"`
+}
+
+// blockRef identifies an indexed head, to notice when it is no longer canonical
+type blockRef struct {
+	Number uint64 `json:"number"`
+	Hash   string `json:"hash"`
+}
+
+var (
+	indexerOnce sync.Once
+	indexer     *Indexer
+)
+
+// EventIndex returns the service wide event indexer, persisted in the configured data directory
+func EventIndex() *Indexer {
+	indexerOnce.Do(func() {
+		path := config.Get().IndexFile()
+		indexer = &Indexer{path: path, state: indexerState{Wallets: map[string]*walletCursor{}}, wake: make(chan struct{}, 1)}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &indexer.state)
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("indexer: %v", err)
+		}
+	})
+	return indexer
+}
+
+// Start indexes the wallets the service deployed on the active network that are not indexed yet and
+// starts following the chain
+func (ix *Indexer) Start() {
+	chainID := int64(0)
+	if network, err := config.Get().Active(); err == nil {
+		chainID = network.ChainID
+	}
+	for _, job := range DeploymentJobs().List() {
+		if job.Status == DeployVerified && job.ChainID == chainID { // The same deployer and nonce give the same address on every chain
+			ix.track(common.HexToAddress(job.ContractAddress), job.BlockNumber)
+		}
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.started {
+		return
+	}
+	ix.started = true
+	go func() {
+		for {
+			if err := ix.follow(); err != nil {
+				log.Printf("indexer: %v, reconnecting", err)
+			}
+			time.Sleep(indexerReconnect)
+		}
+	}()
+}
+
+// Add starts indexing wallet from fromBlock, or from its deployment block when fromBlock is nil
+func (ix *Indexer) Add(wallet common.Address, fromBlock *uint64) (IndexedWallet, error) {
+	from := uint64(0)
+	if fromBlock != nil {
+		from = *fromBlock
+	} else {
+		client, _, err := Dial()
+		if err != nil {
+			return IndexedWallet{}, err
+		}
+		defer client.Close()
+		if from, err = deploymentBlock(context.Background(), client, wallet); err != nil {
+			return IndexedWallet{}, err
+		}
+	}
+	ix.track(wallet, from)
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.status(ix.state.Wallets[strings.ToLower(wallet.Hex())]), nil
+}
+
+// track indexes wallet from block from unless it is indexed already
+func (ix *Indexer) track(wallet common.Address, from uint64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	key := strings.ToLower(wallet.Hex())
+	if ix.state.Wallets[key] != nil {
+		return
+	}
+	ix.state.Wallets[key] = &walletCursor{Address: wallet.Hex(), FromBlock: from, NextBlock: from}
+	if err := ix.save(); err != nil {
+		log.Printf("indexer: %v", err)
+	}
+	select {
+	case ix.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Remove stops indexing wallet and drops its events
+func (ix *Indexer) Remove(wallet common.Address) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	key := strings.ToLower(wallet.Hex())
+	if ix.state.Wallets[key] == nil {
+		return fmt.Errorf("%w: %s", ErrNotIndexed, wallet.Hex())
+	}
+	delete(ix.state.Wallets, key)
+	events := ix.state.Events[:0]
+	for _, e := range ix.state.Events {
+		if !strings.EqualFold(e.Wallet, wallet.Hex()) {
+			events = append(events, e)
+		}
+	}
+	ix.state.Events = events
+	return ix.save()
+}
+
+// List returns the indexed wallets and how far they are indexed
+func (ix *Indexer) List() []IndexedWallet {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	list := []IndexedWallet{}
+	for _, w := range ix.state.Wallets {
+		list = append(list, ix.status(w))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Address < list[j].Address })
+	return list
+}
+
+// Events returns a page of the indexed events of wallet, newest first
+func (ix *Indexer) Events(wallet common.Address, filter EventFilter) (EventPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > maxEventLimit {
+		filter.Limit = 20
+	}
+	page := EventPage{Page: filter.Page, Limit: filter.Limit, Events: []ChainEvent{}}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.state.Wallets[strings.ToLower(wallet.Hex())] == nil {
+		return page, fmt.Errorf("%w: %s", ErrNotIndexed, wallet.Hex())
+	}
+	skip := (filter.Page - 1) * filter.Limit
+	for i := len(ix.state.Events) - 1; i >= 0; i-- {
+		e := ix.state.Events[i]
+		if !strings.EqualFold(e.Wallet, wallet.Hex()) || !filter.matches(e) {
+			continue
+		}
+		if page.Total >= skip && len(page.Events) < filter.Limit {
+			page.Events = append(page.Events, e)
+		}
+		page.Total++
+	}
+	return page, nil
+}
+
+func (f EventFilter) matches(e ChainEvent) bool {
+	switch {
+	case f.Event != "" && e.Event != f.Event,
+		f.TxIndex != nil && (e.TxIndex == nil || *e.TxIndex != *f.TxIndex),
+		f.Owner != "" && !strings.EqualFold(e.Owner, f.Owner),
+		e.BlockNumber < f.FromBlock,
+		f.ToBlock != 0 && e.BlockNumber > f.ToBlock:
+		return false
+	}
+	return true
+}
+
+// proposalHistory returns the history of proposal index of wallet from the index, or false when the
+// wallet is not indexed up to the latest head and the chain has to be asked
+func (ix *Indexer) proposalHistory(wallet common.Address, index uint64) ([]ProposalEvent, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	w := ix.state.Wallets[strings.ToLower(wallet.Hex())]
+	if w == nil || !ix.status(w).Synced {
+		return nil, false
+	}
+	events := []ProposalEvent{}
+	for _, e := range ix.state.Events {
+		if !strings.EqualFold(e.Wallet, wallet.Hex()) || e.TxIndex == nil || *e.TxIndex != index {
+			continue
+		}
+		events = append(events, ProposalEvent{Event: e.Event, Owner: e.Owner, BlockNumber: e.BlockNumber, TxHash: e.TxHash, logIndex: e.LogIndex})
+	}
+	return events, true
+}
+
+// fromBlock returns the block the index started following wallet at
+func (ix *Indexer) fromBlock(wallet common.Address) (uint64, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	w := ix.state.Wallets[strings.ToLower(wallet.Hex())]
+	if w == nil {
+		return 0, false
+	}
+	return w.FromBlock, true
+}
+
+// submissions returns the submitted events of wallet by proposal index from the index, or false when
+// the wallet is not indexed up to the latest head
+func (ix *Indexer) submissions(wallet common.Address) (map[uint64]ChainEvent, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	w := ix.state.Wallets[strings.ToLower(wallet.Hex())]
+	if w == nil || !ix.status(w).Synced {
+		return nil, false
+	}
+	submitted := map[uint64]ChainEvent{}
+	for _, e := range ix.state.Events {
+		if e.Event == "submitted" && e.TxIndex != nil && strings.EqualFold(e.Wallet, wallet.Hex()) {
+			submitted[*e.TxIndex] = e
+		}
+	}
+	return submitted, true
+}
+
+// status describes w with its event count and whether it is synced. Called with ix.mu held.
+func (ix *Indexer) status(w *walletCursor) IndexedWallet {
+	status := IndexedWallet{Address: w.Address, FromBlock: w.FromBlock, NextBlock: w.NextBlock}
+	for _, e := range ix.state.Events {
+		if strings.EqualFold(e.Wallet, w.Address) {
+			status.Events++
+		}
+	}
+	if n := len(ix.state.Heads); n > 0 {
+		status.Synced = w.NextBlock > ix.state.Heads[n-1].Number
+	}
+	return status
+}
+
+// follow syncs on every new head until the connection fails. Heads come from a subscription when the
+// node supports one and from polling otherwise.
+func (ix *Indexer) follow() error {
+	ctx := context.Background()
+	client, err := dialEvents()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := ix.sync(ctx, client); err != nil {
+		return err
+	}
+
+	heads := make(chan *types.Header)
+	var subErr <-chan error
+	var tick <-chan time.Time
+	if sub, err := client.SubscribeNewHead(ctx, heads); err == nil {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	} else {
+		ticker := time.NewTicker(indexerPollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-heads:
+		case <-tick:
+		case <-ix.wake:
+		case err := <-subErr:
+			return err
+		}
+		if err := ix.sync(ctx, client); err != nil {
+			return err
+		}
+	}
+}
+
+// sync rolls back a reorg, indexes every wallet up to the latest head and remembers that head
+func (ix *Indexer) sync(ctx context.Context, client *ethclient.Client) error {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := ix.checkReorg(ctx, client); err != nil {
+		return err
+	}
+
+	ix.mu.Lock()
+	var wallets []walletCursor
+	for _, w := range ix.state.Wallets {
+		wallets = append(wallets, *w)
+	}
+	ix.mu.Unlock()
+	for _, w := range wallets {
+		for from := w.NextBlock; from <= head.Number.Uint64(); from += indexerChunk {
+			to := min(from+indexerChunk-1, head.Number.Uint64())
+			events, err := fetchEvents(ctx, client, common.HexToAddress(w.Address), from, to)
+			if err != nil {
+				return fmt.Errorf("%s blocks %d-%d: %w", w.Address, from, to, err)
+			}
+			if !ix.store(w.Address, events, to+1) {
+				break // Removed meanwhile
+			}
+		}
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	n := len(ix.state.Heads)
+	if n == 0 || ix.state.Heads[n-1].Number < head.Number.Uint64() {
+		ix.state.Heads = append(ix.state.Heads, blockRef{head.Number.Uint64(), head.Hash().Hex()})
+		if len(ix.state.Heads) > indexerReorgDepth {
+			ix.state.Heads = ix.state.Heads[len(ix.state.Heads)-indexerReorgDepth:]
+		}
+	}
+	if !ix.dirty {
+		return nil
+	}
+	return ix.save()
+}
+
+// store adds the events of one chunk of wallet and moves its next block on. Chunks come in block
+// order and a rollback only cuts the newest events, so appending keeps each wallet's events in order.
+// It reports false when the wallet is no longer indexed.
+func (ix *Indexer) store(wallet string, events []ChainEvent, next uint64) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	w := ix.state.Wallets[strings.ToLower(wallet)]
+	if w == nil {
+		return false
+	}
+	ix.state.Events = append(ix.state.Events, events...)
+	w.NextBlock = next
+	ix.dirty = ix.dirty || len(events) > 0
+	return true
+}
+
+// checkReorg compares the remembered heads with the chain and rolls back what a reorg orphaned
+func (ix *Indexer) checkReorg(ctx context.Context, client *ethclient.Client) error {
+	ix.mu.Lock()
+	heads := append([]blockRef(nil), ix.state.Heads...)
+	ix.mu.Unlock()
+
+	ancestor, reorged, err := forkPoint(ctx, client, heads)
+	if err == nil && reorged {
+		ix.rollback(ancestor)
+	}
+	return err
+}
+
+// forkPoint compares the remembered heads (oldest first) with the chain, newest first. When the newest
+// is no longer canonical, it reports reorged and the newest one that still is as the ancestor, where
+// everything after has to be read again.
+func forkPoint(ctx context.Context, client *ethclient.Client, heads []blockRef) (uint64, bool, error) {
+	for i := len(heads) - 1; i >= 0; i-- {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(heads[i].Number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) { // Not found: the new chain is shorter
+			return 0, false, err
+		}
+		if err == nil && header.Hash().Hex() == heads[i].Hash {
+			return heads[i].Number, i < len(heads)-1, nil
+		}
+	}
+	if len(heads) > 0 { // Forked deeper than remembered
+		return heads[0].Number - min(heads[0].Number, indexerReorgDepth), true, nil
+	}
+	return 0, false, nil
+}
+
+// rollback drops the events after block ancestor and indexes those blocks again, saved by the sync
+func (ix *Indexer) rollback(ancestor uint64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	events := ix.state.Events[:0]
+	for _, e := range ix.state.Events {
+		if e.BlockNumber <= ancestor {
+			events = append(events, e)
+		}
+	}
+	log.Printf("indexer: reorg, rolled back %d events after block %d", len(ix.state.Events)-len(events), ancestor)
+	ix.state.Events = events
+	for _, w := range ix.state.Wallets {
+		w.NextBlock = max(min(w.NextBlock, ancestor+1), w.FromBlock)
+	}
+	heads := ix.state.Heads[:0]
+	for _, h := range ix.state.Heads {
+		if h.Number <= ancestor {
+			heads = append(heads, h)
+		}
+	}
+	ix.state.Heads = heads
+	ix.dirty = true
+}
+
+// fetchEvents reads and decodes the events of wallet in blocks from to to
+func fetchEvents(ctx context.Context, client *ethclient.Client, wallet common.Address, from, to uint64) ([]ChainEvent, error) {
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{wallet},
+	})
+	if err != nil {
+		return nil, err
+	}
+	filterer, err := contracts.NewContractsFilterer(wallet, client)
+	if err != nil {
+		return nil, err
+	}
+
+	times := map[common.Hash]time.Time{}
+	var events []ChainEvent
+	for _, raw := range logs {
+		e, err := chainEvent(ctx, client, filterer, raw, times)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			events = append(events, *e)
+		}
+	}
+	return events, nil
+}
+
+// chainEvent decodes raw with its block time, nil when it is not an event of the multisig. A log with
+// a multisig event's topic that does not decode is logged and skipped. Block times are cached in times.
+func chainEvent(ctx context.Context, client *ethclient.Client, filterer *contracts.ContractsFilterer, raw types.Log, times map[common.Hash]time.Time) (*ChainEvent, error) {
+	e, err := decodeEvent(filterer, raw)
+	if err != nil {
+		log.Printf("events %s: skipping log %d of tx %s in block %d, it does not decode: %v", raw.Address.Hex(), raw.Index, raw.TxHash.Hex(), raw.BlockNumber, err)
+		return nil, nil
+	}
+	if e == nil {
+		return nil, nil
+	}
+	if _, ok := times[raw.BlockHash]; !ok {
+		header, err := client.HeaderByHash(ctx, raw.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		times[raw.BlockHash] = time.Unix(int64(header.Time), 0).UTC()
+	}
+	e.Wallet, e.BlockNumber, e.BlockHash, e.Time = raw.Address.Hex(), raw.BlockNumber, raw.BlockHash.Hex(), times[raw.BlockHash]
+	e.TxHash, e.LogIndex, e.Removed = raw.TxHash.Hex(), raw.Index, raw.Removed
+	return e, nil
+}
+
+// decodeEvent decodes a log of the multisig with the generated bindings, nil for unknown events
+func decodeEvent(f *contracts.ContractsFilterer, raw types.Log) (*ChainEvent, error) {
+	if len(raw.Topics) == 0 {
+		return nil, nil
+	}
+	parsed, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	event, err := parsed.EventByID(raw.Topics[0])
+	if err != nil {
+		return nil, nil
+	}
+	index := func(i *big.Int) *uint64 {
+		n := i.Uint64()
+		return &n
+	}
+
+	switch event.Name {
+	case "Deposit":
+		ev, err := f.ParseDeposit(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "deposit", From: ev.Sender.Hex(), Value: ev.Amount.String()}, nil
+	case "SubmitTransaction":
+		ev, err := f.ParseSubmitTransaction(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "submitted", TxIndex: index(ev.TxIndex), To: ev.To.Hex(), Value: ev.Value.String(), Data: hexutil.Encode(ev.Data)}, nil
+	case "ConfirmTransaction":
+		ev, err := f.ParseConfirmTransaction(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "confirmed", TxIndex: index(ev.TxIndex), Owner: ev.Owner.Hex()}, nil
+	case "RevokeConfirmation":
+		ev, err := f.ParseRevokeConfirmation(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "revoked", TxIndex: index(ev.TxIndex), Owner: ev.Owner.Hex()}, nil
+	case "ExecuteTransaction":
+		ev, err := f.ParseExecuteTransaction(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "executed", TxIndex: index(ev.TxIndex)}, nil
+	case "OwnerAddition":
+		ev, err := f.ParseOwnerAddition(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "ownerAdded", Owner: ev.Owner.Hex()}, nil
+	case "OwnerRemoval":
+		ev, err := f.ParseOwnerRemoval(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "ownerRemoved", Owner: ev.Owner.Hex()}, nil
+	case "RequirementChange":
+		ev, err := f.ParseRequirementChange(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "requirementChanged", RequiredConfirmations: ev.RequiredConfirmations.Uint64()}, nil
+	case "TimelockChange":
+		ev, err := f.ParseTimelockChange(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &ChainEvent{Event: "timelockChanged", Delay: ev.Delay.Uint64(), MinValue: ev.MinValue.String()}, nil
+	}
+	return nil, nil
+}
+
+// deploymentBlock finds the block wallet was deployed in by bisecting over the blocks where it has code.
+// This needs historical state, which pruned nodes may not have; give fromBlock for those.
+func deploymentBlock(ctx context.Context, client *ethclient.Client, wallet common.Address) (uint64, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range DeploymentJobs().List() {
+		if job.ChainID == chainID.Int64() && strings.EqualFold(job.ContractAddress, wallet.Hex()) && job.BlockNumber != 0 {
+			return job.BlockNumber, nil
+		}
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	code, err := client.CodeAt(ctx, wallet, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(code) == 0 {
+		return 0, fmt.Errorf("%w: %s", bind.ErrNoCode, wallet.Hex())
+	}
+	low, high := uint64(0), head // The code exists at high
+	for low < high {
+		mid := low + (high-low)/2
+		code, err := client.CodeAt(ctx, wallet, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("finding the deployment block (give fromBlock instead): %w", err)
+		}
+		if len(code) > 0 {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// scanLogs calls fetch for consecutive ranges of at most indexerChunk blocks from wallet's deployment
+// block to the head, so log reads outside the index never ask the node to scan from block 0. The start
+// comes from the index when it follows the wallet (synced or not) and is searched for otherwise.
+func scanLogs(ctx context.Context, client *ethclient.Client, wallet common.Address, fetch func(opts *bind.FilterOpts) error) error {
+	start, ok := EventIndex().fromBlock(wallet)
+	if !ok {
+		var err error
+		if start, err = deploymentBlock(ctx, client, wallet); err != nil {
+			return fmt.Errorf("%w (%v); add the wallet to the index with POST /index/wallets and a fromBlock", errNoDeploymentBlock, err)
+		}
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for from := start; from <= head; from += indexerChunk {
+		to := min(from+indexerChunk-1, head)
+		if err := fetch(&bind.FilterOpts{Start: from, End: &to, Context: ctx}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// save writes the wallets, events and heads to the index file. Called with ix.mu held.
+func (ix *Indexer) save() error {
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ix.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := ix.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, ix.path); err != nil { // Atomic replace so a crash never leaves half a file
+		return err
+	}
+	ix.dirty = false
+	return nil
+}
@@ -0,0 +1,67 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func depositLog(t *testing.T, data []byte) types.Log {
+	t.Helper()
+	parsed, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return types.Log{
+		Address:     common.HexToAddress(testWallet),
+		Topics:      []common.Hash{parsed.Events["Deposit"].ID, common.BytesToHash(common.HexToAddress(testRecipient).Bytes())},
+		Data:        data,
+		BlockNumber: 42,
+		TxHash:      common.HexToHash("0xabc"),
+		Index:       3,
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	f, err := contracts.NewContractsFilterer(common.HexToAddress(testWallet), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := decodeEvent(f, depositLog(t, math.U256Bytes(big.NewInt(5))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || e.Event != "deposit" || e.From != testRecipient || e.Value != "5" {
+		t.Fatalf("decoded %+v, want a deposit of 5 wei from %s", e, testRecipient)
+	}
+	if e, err := decodeEvent(f, types.Log{Topics: []common.Hash{common.HexToHash("0x1234")}}); e != nil || err != nil {
+		t.Fatalf("unknown event decoded to %+v, %v", e, err)
+	}
+}
+
+// A multisig event that does not decode is skipped, but never silently
+func TestChainEventLogsUndecodable(t *testing.T) {
+	f, err := contracts.NewContractsFilterer(common.HexToAddress(testWallet), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&out)
+
+	e, err := chainEvent(context.Background(), nil, f, depositLog(t, []byte{1, 2, 3}), nil) // Amount truncated
+	if e != nil || err != nil {
+		t.Fatalf("got %+v, %v; want the log skipped", e, err)
+	}
+	if !strings.Contains(out.String(), "skipping log 3 of tx "+common.HexToHash("0xabc").Hex()+" in block 42") {
+		t.Fatalf("logged %q", out.String())
+	}
+}
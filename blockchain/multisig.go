@@ -1,408 +1,72 @@
 package blockchain
 
 import (
-	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
-
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// Deployed actual contract ABI and Bytecode
-const MultisigWalletABI = `[
-
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "_txIndex",
-				"type": "uint256"
-			}
-		],
-		"name": "confirmTransaction",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address[]",
-				"name": "_owners",
-				"type": "address[]"
-			},
-			{
-				"internalType": "uint256",
-				"name": "_requiredConfirmations",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "nonpayable",
-		"type": "constructor"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "address",
-				"name": "owner",
-				"type": "address"
-			},
-			{
-				"indexed": true,
-				"internalType": "uint256",
-				"name": "txIndex",
-				"type": "uint256"
-			}
-		],
-		"name": "ConfirmTransaction",
-		"type": "event"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "address",
-				"name": "sender",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "amount",
-				"type": "uint256"
-			}
-		],
-		"name": "Deposit",
-		"type": "event"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "_txIndex",
-				"type": "uint256"
-			}
-		],
-		"name": "executeTransaction",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "uint256",
-				"name": "txIndex",
-				"type": "uint256"
-			}
-		],
-		"name": "ExecuteTransaction",
-		"type": "event"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "_to",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "_value",
-				"type": "uint256"
-			},
-			{
-				"internalType": "bytes",
-				"name": "_data",
-				"type": "bytes"
-			}
-		],
-		"name": "submitTransaction",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"anonymous": false,
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "uint256",
-				"name": "txIndex",
-				"type": "uint256"
-			},
-			{
-				"indexed": true,
-				"internalType": "address",
-				"name": "to",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "uint256",
-				"name": "value",
-				"type": "uint256"
-			},
-			{
-				"indexed": false,
-				"internalType": "bytes",
-				"name": "data",
-				"type": "bytes"
-			}
-		],
-		"name": "SubmitTransaction",
-		"type": "event"
-	},
-	{
-		"stateMutability": "payable",
-		"type": "receive"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "_txIndex",
-				"type": "uint256"
-			}
-		],
-		"name": "getTransaction",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "to",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "value",
-				"type": "uint256"
-			},
-			{
-				"internalType": "bytes",
-				"name": "data",
-				"type": "bytes"
-			},
-			{
-				"internalType": "bool",
-				"name": "executed",
-				"type": "bool"
-			},
-			{
-				"internalType": "uint256",
-				"name": "confirmations",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "getTransactionCount",
-		"outputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			},
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"name": "isConfirmed",
-		"outputs": [
-			{
-				"internalType": "bool",
-				"name": "",
-				"type": "bool"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"name": "isOwner",
-		"outputs": [
-			{
-				"internalType": "bool",
-				"name": "",
-				"type": "bool"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"name": "owners",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "requiredConfirmations",
-		"outputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "uint256",
-				"name": "",
-				"type": "uint256"
-			}
-		],
-		"name": "transactions",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "to",
-				"type": "address"
-			},
-			{
-				"internalType": "uint256",
-				"name": "value",
-				"type": "uint256"
-			},
-			{
-				"internalType": "bytes",
-				"name": "data",
-				"type": "bytes"
-			},
-			{
-				"internalType": "bool",
-				"name": "executed",
-				"type": "bool"
-			},
-			{
-				"internalType": "uint256",
-				"name": "confirmations",
-				"type": "uint256"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	}
-]`
-const MultisigWalletBytecode = `608060405234801561000f575f5ffd5b50604051611e62380380611e628339818101604052810190610031919061046c565b5f825111610074576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161006b90610520565b60405180910390fd5b5f81118015610084575081518111155b6100c3576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016100ba90610588565b60405180910390fd5b5f5b825181101561026f575f8382815181106100e2576100e16105a6565b5b602002602001015190505f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff161415801561016f575060015f8273ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16155b6101ae576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016101a59061061d565b60405180910390fd5b6001805f8373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f6101000a81548160ff0219169083151502179055505f81908060018154018082558091505060019003905f5260205f20015f9091909190916101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055505080806001019150506100c5565b5080600281905550505061063b565b5f604051905090565b5f5ffd5b5f5ffd5b5f5ffd5b5f601f19601f8301169050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52604160045260245ffd5b6102d982610293565b810181811067ffffffffffffffff821117156102f8576102f76102a3565b5b80604052505050565b5f61030a61027e565b905061031682826102d0565b919050565b5f67ffffffffffffffff821115610335576103346102a3565b5b602082029050602081019050919050565b5f5ffd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f6103738261034a565b9050919050565b61038381610369565b811461038d575f5ffd5b50565b5f8151905061039e8161037a565b92915050565b5f6103b66103b18461031b565b610301565b905080838252602082019050602084028301858111156103d9576103d8610346565b5b835b8181101561040257806103ee8882610390565b8452602084019350506020810190506103db565b5050509392505050565b5f82601f8301126104205761041f61028f565b5b81516104308482602086016103a4565b91505092915050565b5f819050919050565b61044b81610439565b8114610455575f5ffd5b50565b5f8151905061046681610442565b92915050565b5f5f6040838503121561048257610481610287565b5b5f83015167ffffffffffffffff81111561049f5761049e61028b565b5b6104ab8582860161040c565b92505060206104bc85828601610458565b9150509250929050565b5f82825260208201905092915050565b7f6f776e65727320726571756972656400000000000000000000000000000000005f82015250565b5f61050a600f836104c6565b9150610515826104d6565b602082019050919050565b5f6020820190508181035f830152610537816104fe565b9050919050565b7f696e76616c696420636f6e6669726d6174696f6e7300000000000000000000005f82015250565b5f6105726015836104c6565b915061057d8261053e565b602082019050919050565b5f6020820190508181035f83015261059f81610566565b9050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52603260045260245ffd5b7f696e76616c6964206f776e6572000000000000000000000000000000000000005f82015250565b5f610607600d836104c6565b9150610612826105d3565b602082019050919050565b5f6020820190508181035f830152610634816105fb565b9050919050565b61181a806106485f395ff3fe608060405260043610610094575f3560e01c806382e717f71161005857806382e717f71461020b5780639ace38c214610235578063c01a8c8414610275578063c64274741461029d578063ee22610b146102c5576100e9565b8063025e7c27146100ed5780632e7700f0146101295780632f54bf6e1461015357806333ea3dc81461018f57806380f59a65146101cf576100e9565b366100e9573373ffffffffffffffffffffffffffffffffffffffff167fe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c346040516100df9190610d0d565b60405180910390a2005b5f5ffd5b3480156100f8575f5ffd5b50610113600480360381019061010e9190610d61565b6102ed565b6040516101209190610dcb565b60405180910390f35b348015610134575f5ffd5b5061013d610327565b60405161014a9190610d0d565b60405180910390f35b34801561015e575f5ffd5b5061017960048036038101906101749190610e0e565b610333565b6040516101869190610e53565b60405180910390f35b34801561019a575f5ffd5b506101b560048036038101906101b09190610d61565b610350565b6040516101c6959493929190610edc565b60405180910390f35b3480156101da575f5ffd5b506101f560048036038101906101f09190610f34565b610459565b6040516102029190610e53565b60405180910390f35b348015610216575f5ffd5b5061021f610483565b60405161022c9190610d0d565b60405180910390f35b348015610240575f5ffd5b5061025b60048036038101906102569190610d61565b610489565b60405161026c959493929190610edc565b60405180910390f35b348015610280575f5ffd5b5061029b60048036038101906102969190610d61565b61057b565b005b3480156102a8575f5ffd5b506102c360048036038101906102be919061109e565b61083f565b005b3480156102d0575f5ffd5b506102eb60048036038101906102e69190610d61565b610a24565b005b5f81815481106102fb575f80fd5b905f5260205f20015f915054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b5f600380549050905090565b6001602052805f5260405f205f915054906101000a900460ff1681565b5f5f60605f5f5f6003878154811061036b5761036a61110a565b5b905f5260205f2090600502019050805f015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff16816001015482600201836003015f9054906101000a900460ff1684600401548280546103c790611164565b80601f01602080910402602001604051908101604052809291908181526020018280546103f390611164565b801561043e5780601f106104155761010080835404028352916020019161043e565b820191905f5260205f20905b81548152906001019060200180831161042157829003601f168201915b50505050509250955095509550955095505091939590929450565b6004602052815f5260405f20602052805f5260405f205f915091509054906101000a900460ff1681565b60025481565b60038181548110610498575f80fd5b905f5260205f2090600502015f91509050805f015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff16908060010154908060020180546104e290611164565b80601f016020809104026020016040519081016040528092919081815260200182805461050e90611164565b80156105595780601f1061053057610100808354040283529160200191610559565b820191905f5260205f20905b81548152906001019060200180831161053c57829003601f168201915b505050505090806003015f9054906101000a900460ff16908060040154905085565b60015f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16610604576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016105fb906111ee565b60405180910390fd5b80600380549050811061064c576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161064390611256565b60405180910390fd5b8160045f8281526020019081526020015f205f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16156106e6576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016106dd906112be565b60405180910390fd5b82600381815481106106fb576106fa61110a565b5b905f5260205f2090600502016003015f9054906101000a900460ff1615610757576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161074e90611326565b60405180910390fd5b600160045f8681526020019081526020015f205f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f6101000a81548160ff0219169083151502179055506001600385815481106107d1576107d061110a565b5b905f5260205f2090600502016004015f8282546107ee9190611371565b92505081905550833373ffffffffffffffffffffffffffffffffffffffff167f5cbe105e36805f7820e291f799d5794ff948af2a5f664e580382defb6339004160405160405180910390a350505050565b60015f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff166108c8576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016108bf906111ee565b60405180910390fd5b60036040518060a001604052808573ffffffffffffffffffffffffffffffffffffffff1681526020018481526020018381526020015f151581526020015f815250908060018154018082558091505060019003905f5260205f2090600502015f909190919091505f820151815f015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055506020820151816001015560408201518160020190816109929190611544565b506060820151816003015f6101000a81548160ff0219169083151502179055506080820151816004015550508273ffffffffffffffffffffffffffffffffffffffff1660016003805490506109e79190611613565b7ec2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe8484604051610a17929190611646565b60405180910390a3505050565b60015f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16610aad576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610aa4906111ee565b60405180910390fd5b806003805490508110610af5576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610aec90611256565b60405180910390fd5b8160038181548110610b0a57610b0961110a565b5b905f5260205f2090600502016003015f9054906101000a900460ff1615610b66576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610b5d90611326565b60405180910390fd5b5f60038481548110610b7b57610b7a61110a565b5b905f5260205f209060050201905060025481600401541015610bd2576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610bc9906116be565b60405180910390fd5b6001816003015f6101000a81548160ff0219169083151502179055505f815f015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16826001015483600201604051610c3e9190611766565b5f6040518083038185875af1925050503d805f8114610c78576040519150601f19603f3d011682016040523d82523d5f602084013e610c7d565b606091505b5050905080610cc1576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610cb8906117c6565b60405180910390fd5b847fae30dc3f11bb6b178aafe5e7fc568fb6d87200068a944a8015c0db1b4533dbb860405160405180910390a25050505050565b5f819050919050565b610d0781610cf5565b82525050565b5f602082019050610d205f830184610cfe565b92915050565b5f604051905090565b5f5ffd5b5f5ffd5b610d4081610cf5565b8114610d4a575f5ffd5b50565b5f81359050610d5b81610d37565b92915050565b5f60208284031215610d7657610d75610d2f565b5b5f610d8384828501610d4d565b91505092915050565b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f610db582610d8c565b9050919050565b610dc581610dab565b82525050565b5f602082019050610dde5f830184610dbc565b92915050565b610ded81610dab565b8114610df7575f5ffd5b50565b5f81359050610e0881610de4565b92915050565b5f60208284031215610e2357610e22610d2f565b5b5f610e3084828501610dfa565b91505092915050565b5f8115159050919050565b610e4d81610e39565b82525050565b5f602082019050610e665f830184610e44565b92915050565b5f81519050919050565b5f82825260208201905092915050565b8281835e5f83830152505050565b5f601f19601f8301169050919050565b5f610eae82610e6c565b610eb88185610e76565b9350610ec8818560208601610e86565b610ed181610e94565b840191505092915050565b5f60a082019050610eef5f830188610dbc565b610efc6020830187610cfe565b8181036040830152610f0e8186610ea4565b9050610f1d6060830185610e44565b610f2a6080830184610cfe565b9695505050505050565b5f5f60408385031215610f4a57610f49610d2f565b5b5f610f5785828601610d4d565b9250506020610f6885828601610dfa565b9150509250929050565b5f5ffd5b5f5ffd5b7f4e487b71000000000000000000000000000000000000000000000000000000005f52604160045260245ffd5b610fb082610e94565b810181811067ffffffffffffffff82111715610fcf57610fce610f7a565b5b80604052505050565b5f610fe1610d26565b9050610fed8282610fa7565b919050565b5f67ffffffffffffffff82111561100c5761100b610f7a565b5b61101582610e94565b9050602081019050919050565b828183375f83830152505050565b5f61104261103d84610ff2565b610fd8565b90508281526020810184848401111561105e5761105d610f76565b5b611069848285611022565b509392505050565b5f82601f83011261108557611084610f72565b5b8135611095848260208601611030565b91505092915050565b5f5f5f606084860312156110b5576110b4610d2f565b5b5f6110c286828701610dfa565b93505060206110d386828701610d4d565b925050604084013567ffffffffffffffff8111156110f4576110f3610d33565b5b61110086828701611071565b9150509250925092565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52603260045260245ffd5b7f4e487b71000000000000000000000000000000000000000000000000000000005f52602260045260245ffd5b5f600282049050600182168061117b57607f821691505b60208210810361118e5761118d611137565b5b50919050565b5f82825260208201905092915050565b7f6e6f74206f776e657200000000000000000000000000000000000000000000005f82015250565b5f6111d8600983611194565b91506111e3826111a4565b602082019050919050565b5f6020820190508181035f830152611205816111cc565b9050919050565b7f747820646f6573206e6f742065786973740000000000000000000000000000005f82015250565b5f611240601183611194565b915061124b8261120c565b602082019050919050565b5f6020820190508181035f83015261126d81611234565b9050919050565b7f616c726561647920636f6e6669726d65640000000000000000000000000000005f82015250565b5f6112a8601183611194565b91506112b382611274565b602082019050919050565b5f6020820190508181035f8301526112d58161129c565b9050919050565b7f616c7265616479206578656375746564000000000000000000000000000000005f82015250565b5f611310601083611194565b915061131b826112dc565b602082019050919050565b5f6020820190508181035f83015261133d81611304565b9050919050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52601160045260245ffd5b5f61137b82610cf5565b915061138683610cf5565b925082820190508082111561139e5761139d611344565b5b92915050565b5f819050815f5260205f209050919050565b5f6020601f8301049050919050565b5f82821b905092915050565b5f600883026114007fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff826113c5565b61140a86836113c5565b95508019841693508086168417925050509392505050565b5f819050919050565b5f61144561144061143b84610cf5565b611422565b610cf5565b9050919050565b5f819050919050565b61145e8361142b565b61147261146a8261144c565b8484546113d1565b825550505050565b5f5f905090565b61148961147a565b611494818484611455565b505050565b5b818110156114b7576114ac5f82611481565b60018101905061149a565b5050565b601f8211156114fc576114cd816113a4565b6114d6846113b6565b810160208510156114e5578190505b6114f96114f1856113b6565b830182611499565b50505b505050565b5f82821c905092915050565b5f61151c5f1984600802611501565b1980831691505092915050565b5f611534838361150d565b9150826002028217905092915050565b61154d82610e6c565b67ffffffffffffffff81111561156657611565610f7a565b5b6115708254611164565b61157b8282856114bb565b5f60209050601f8311600181146115ac575f841561159a578287015190505b6115a48582611529565b86555061160b565b601f1984166115ba866113a4565b5f5b828110156115e1578489015182556001820191506020850194506020810190506115bc565b868310156115fe57848901516115fa601f89168261150d565b8355505b6001600288020188555050505b505050505050565b5f61161d82610cf5565b915061162883610cf5565b92508282039050818111156116405761163f611344565b5b92915050565b5f6040820190506116595f830185610cfe565b818103602083015261166b8184610ea4565b90509392505050565b7f6e6f7420656e6f75676820636f6e6669726d6174696f6e7300000000000000005f82015250565b5f6116a8601883611194565b91506116b382611674565b602082019050919050565b5f6020820190508181035f8301526116d58161169c565b9050919050565b5f81905092915050565b5f81546116f281611164565b6116fc81866116dc565b9450600182165f8114611716576001811461172b5761175d565b60ff198316865281151582028601935061175d565b611734856113a4565b5f5b8381101561175557815481890152600182019150602081019050611736565b838801955050505b50505092915050565b5f61177182846116e6565b915081905092915050565b7f7478206661696c656400000000000000000000000000000000000000000000005f82015250565b5f6117b0600983611194565b91506117bb8261177c565b602082019050919050565b5f6020820190508181035f8301526117dd816117a4565b905091905056fea26469706673582212204a4b1983108bb7f24ce9b61e1555cb2bbd5caa2dd2e9171e48536299a8c1259664736f6c634300081b0033`
-
-func DeployMultisigWallet(rpcURL string, privateKeyHex string, owners []common.Address, requiredConfirmations uint8) (common.Address, error) {
-	client, err := ethclient.Dial(rpcURL) //connection to the network
-	if err != nil {
-		return common.Address{}, fmt.Errorf("failed to connect to Ethereum client: %v", err)
-	}
+// MultisigWalletBytecode is the contract's creation code in hex, generated with the bindings from
+// contracts/MultisigWallet.sol
+var MultisigWalletBytecode = strings.TrimPrefix(contracts.ContractsMetaData.Bin, "0x")
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex) //converting Ecdsa to hex
+// DeployMultisigWallet deploys a new multisig contract paid for and signed by s. It returns once the
+// deployment is broadcast; the job then waits for the receipt to be confirmations blocks deep (0 means
+// the network's default) and verifies the contract. Poll it with DeploymentJobs().Get or Wait. With
+// opts.DryRun only the Simulation of the deployment is returned.
+func DeployMultisigWallet(s signer.Signer, owners []common.Address, requiredConfirmations uint8, confirmations uint64, opts TxOptions) (DeployJob, *Simulation, error) {
+	client, network, err := Dial() //connection to the configured network
 	if err != nil {
-		return common.Address{}, fmt.Errorf("invalid private key: %v", err)
+		return DeployJob{}, nil, err
 	}
+	defer client.Close()
 
-	publicKey := privateKey.Public().(*ecdsa.PublicKey) // Derive the public address from the private key
-	fromAddress := crypto.PubkeyToAddress(*publicKey)
+	fromAddress := s.Address()             // Deployer account of the signer
+	chainID := big.NewInt(network.ChainID) // Chain ID verified by Dial
 
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress) // Get the nonce for the sender address
+	parsedABI, err := contracts.ContractsMetaData.GetAbi()
 	if err != nil {
-		return common.Address{}, err
+		return DeployJob{}, nil, fmt.Errorf("failed to parse ABI: %v", err)
 	}
 
-	gasPrice, err := client.SuggestGasPrice(context.Background()) // Suggest an appropriate gas price for the transaction
+	input, err := parsedABI.Pack("", owners, new(big.Int).SetUint64(uint64(requiredConfirmations))) // setting the required owners and conformantions (uint256 in the ABI)
 	if err != nil {
-		return common.Address{}, err
+		return DeployJob{}, nil, err
 	}
+	code := common.FromHex(MultisigWalletBytecode + fmt.Sprintf("%x", input)) // Creation code followed by the constructor arguments
 
-	chainID, err := client.NetworkID(context.Background()) // Get the chain ID
-	if err != nil {
-		return common.Address{}, err
+	// Gas is estimated for the full bytecode; the nonce manager hands out the nonce, which also fixes the contract address
+	request := DeployMultisigRequest{RequiredConfirmations: requiredConfirmations, Confirmations: confirmations, TxOptions: opts}
+	for _, owner := range owners {
+		request.Owners = append(request.Owners, owner.Hex())
 	}
-
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID) //Create an authorized transactor using the private key and chain ID
-	if err != nil {
-		return common.Address{}, err
+	signedTx, sim, err := signAndSend(client, chainID, s, nil, big.NewInt(0), code, opts, "deploy", request)
+	if err != nil || sim != nil { // A dry run starts no job
+		return DeployJob{}, sim, err
 	}
-	auth.Nonce = big.NewInt(int64(nonce)) // Ensures the transaction is unique.
-	auth.Value = big.NewInt(0)            // in wei 0 ethers are send while deploying
-	auth.GasLimit = uint64(300000)        // in units // Set gas limit for deployment
-	auth.GasPrice = gasPrice              // Set suggested gas price
 
-	parsedABI, err := abi.JSON(strings.NewReader(MultisigWalletABI))
-	if err != nil {
-		return common.Address{}, fmt.Errorf("failed to parse ABI: %v", err)
-	}
+	log.Printf("Contract deployment tx sent: %s", signedTx.Hash().Hex())
 
-	input, err := parsedABI.Pack("", owners, requiredConfirmations) // setting the required owners and conformantions
-	if err != nil {
-		return common.Address{}, err
+	if confirmations == 0 {
+		confirmations = network.Confirmations
 	}
-	//Creates a new transaction to deploy the contract
-	tx := types.NewContractCreation(nonce, big.NewInt(0), auth.GasLimit, gasPrice, common.FromHex(MultisigWalletBytecode+fmt.Sprintf("%x", input)))
-
-	signedTx, err := auth.Signer(fromAddress, tx) // Signs the transaction with the sender’s private key
-	if err != nil {
-		return common.Address{}, err
+	if confirmations == 0 {
+		confirmations = 1
 	}
-
-	err = client.SendTransaction(context.Background(), signedTx) //Sends the signed transaction to the Ethereum network for mining.
-	if err != nil {
-		return common.Address{}, err
+	job := &DeployJob{
+		ID:                    signedTx.Hash().Hex(),
+		ContractAddress:       crypto.CreateAddress(fromAddress, signedTx.Nonce()).Hex(), //Derives the deployed contract’s address from the sender’s address and nonce.
+		Deployer:              fromAddress.Hex(),
+		Owners:                request.Owners,
+		RequiredConfirmations: requiredConfirmations,
+		ChainID:               network.ChainID,
+		Depth:                 confirmations,
 	}
-
-	log.Printf("Contract deployment tx sent: %s", signedTx.Hash().Hex())
-
-	// Wait or monitor for confirmation, or return address
-	contractAddress := crypto.CreateAddress(fromAddress, nonce) //Derives the deployed contract’s address from the sender’s address and nonce.
-	return contractAddress, nil
+	return DeploymentJobs().start(job), nil, nil // Wait for the receipt and verify in the background
 }
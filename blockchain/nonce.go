@@ -0,0 +1,325 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NonceManager hands out transaction nonces per account so that concurrent requests from the same
+// account never share one. It remembers which nonces are in flight (handed out, not yet mined),
+// reuses nonces whose transaction never reached the node (gaps), follows the node when the account
+// is used elsewhere and keeps its state in a JSON file across restarts.
+type NonceManager struct {
+	path     string
+	mu       sync.Mutex                // Guards accounts and the file
+	locks    map[string]*sync.Mutex    // Per account, held while a nonce is chosen
+	accounts map[string]*accountNonces // By chain ID and address
+}
+
+// accountNonces is the persisted state of one account
+type accountNonces struct {
+	Next     uint64            `json:"next"`     // Lowest nonce never handed out
+	InFlight map[uint64]string `json:"inFlight"` // Handed out and not known to be mined: nonce to tx hash ("" while signing)
+}
+
+// NonceState describes the nonces of an account as the manager sees them
+type NonceState struct {
+	Next     uint64            `json:"next"`
+	InFlight map[uint64]string `json:"inFlight"`
+}
+
+var (
+	noncesOnce sync.Once
+	nonces     *NonceManager
+)
+
+// Nonces returns the service wide nonce manager, persisted in the configured data directory
+func Nonces() *NonceManager {
+	noncesOnce.Do(func() {
+		var err error
+		if nonces, err = NewNonceManager(config.Get().NonceFile()); err != nil {
+			log.Printf("nonce manager: %v, starting from the node's nonces", err) // Everything in the file can be rebuilt from the chain
+			nonces = &NonceManager{path: config.Get().NonceFile(), locks: map[string]*sync.Mutex{}, accounts: map[string]*accountNonces{}}
+		}
+	})
+	return nonces
+}
+
+// NewNonceManager loads the nonce state persisted at path (a missing file means no state yet)
+func NewNonceManager(path string) (*NonceManager, error) {
+	m := &NonceManager{path: path, locks: map[string]*sync.Mutex{}, accounts: map[string]*accountNonces{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m.accounts); err != nil {
+		return nil, fmt.Errorf("corrupt nonce file %s: %w", path, err)
+	}
+	for _, a := range m.accounts {
+		if a.InFlight == nil {
+			a.InFlight = map[uint64]string{}
+		}
+		for nonce, hash := range a.InFlight {
+			if hash == "" {
+				delete(a.InFlight, nonce) // Reserved by a process that stopped before broadcasting, a gap now
+			}
+		}
+	}
+	return m, nil
+}
+
+// Next reserves a nonce for address. It syncs with the node first: nonces mined meanwhile are
+// forgotten, a node ahead of us (the account sent elsewhere) moves Next forward, and the lowest
+// gap, a nonce handed out whose transaction the node does not know, is handed out again.
+// Every reserved nonce must be followed by Sent or Failed.
+func (m *NonceManager) Next(ctx context.Context, client *ethclient.Client, chainID *big.Int, address common.Address) (uint64, error) {
+	key := nonceKey(chainID, address)
+	lock := m.lock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	gaps, err := m.sync(ctx, client, key, address)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a := m.account(key)
+	nonce := a.Next
+	if len(gaps) > 0 {
+		nonce = gaps[0]
+		log.Printf("nonce manager: reusing nonce %d of %s, its transaction never reached the node", nonce, address.Hex())
+	} else {
+		a.Next++
+	}
+	a.InFlight[nonce] = ""
+	return nonce, m.save()
+}
+
+// Sent records the transaction broadcast with nonce
+func (m *NonceManager) Sent(chainID *big.Int, address common.Address, nonce uint64, hash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.account(nonceKey(chainID, address)).InFlight[nonce] = hash.Hex()
+	if err := m.save(); err != nil {
+		log.Printf("nonce manager: %v", err)
+	}
+}
+
+// Failed releases nonce after its transaction could not be signed or the node rejected it
+func (m *NonceManager) Failed(chainID *big.Int, address common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a := m.account(nonceKey(chainID, address))
+	delete(a.InFlight, nonce)
+	if nonce+1 == a.Next {
+		a.Next-- // Last one handed out, simply take it back; otherwise the next sync sees a gap
+	}
+	if err := m.save(); err != nil {
+		log.Printf("nonce manager: %v", err)
+	}
+}
+
+// Resync forgets the nonces of address the node has already taken and moves Next past them,
+// after the node rejected a nonce (too low, already known) because the account was used elsewhere
+func (m *NonceManager) Resync(ctx context.Context, client *ethclient.Client, chainID *big.Int, address common.Address) error {
+	key := nonceKey(chainID, address)
+	lock := m.lock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	pending, err := client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a := m.account(key)
+	for nonce := range a.InFlight {
+		if nonce < pending {
+			delete(a.InFlight, nonce) // Taken by the node, by our transaction or another one
+		}
+	}
+	if pending > a.Next {
+		a.Next = pending
+	}
+	return m.save()
+}
+
+// State returns a copy of what the manager knows about address
+func (m *NonceManager) State(chainID *big.Int, address common.Address) NonceState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a := m.account(nonceKey(chainID, address))
+	state := NonceState{Next: a.Next, InFlight: map[uint64]string{}}
+	for nonce, hash := range a.InFlight {
+		state.InFlight[nonce] = hash
+	}
+	return state
+}
+
+// sync reconciles the account with the node and returns the gaps, lowest first. Called with the account lock held.
+func (m *NonceManager) sync(ctx context.Context, client *ethclient.Client, key string, address common.Address) ([]uint64, error) {
+	pending, err := client.PendingNonceAt(ctx, address) // Includes the node's own pool
+	if err != nil {
+		return nil, err
+	}
+	mined, err := client.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	a := m.account(key)
+	for nonce := range a.InFlight {
+		if nonce < mined {
+			delete(a.InFlight, nonce)
+		}
+	}
+	if pending > a.Next {
+		a.Next = pending
+	}
+	// Nonces at or above the node's pending nonce are either queued behind a gap or unknown to the node
+	candidates := map[uint64]string{}
+	for nonce := pending; nonce < a.Next; nonce++ {
+		hash, ok := a.InFlight[nonce]
+		if !ok || hash != "" { // "" is being signed right now
+			candidates[nonce] = hash
+		}
+	}
+	m.mu.Unlock()
+
+	var gaps []uint64
+	for nonce, hash := range candidates {
+		if hash != "" {
+			if _, _, err := client.TransactionByHash(ctx, common.HexToHash(hash)); !errors.Is(err, ethereum.NotFound) {
+				continue // Known to the node, only waiting for an earlier nonce
+			}
+		}
+		gaps = append(gaps, nonce)
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps, nil
+}
+
+func (m *NonceManager) lock(key string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] == nil {
+		m.locks[key] = &sync.Mutex{}
+	}
+	return m.locks[key]
+}
+
+// account returns the state of key, creating it. Called with m.mu held.
+func (m *NonceManager) account(key string) *accountNonces {
+	a, ok := m.accounts[key]
+	if !ok {
+		a = &accountNonces{InFlight: map[uint64]string{}}
+		m.accounts[key] = a
+	}
+	return a
+}
+
+// save writes the state to the nonce file. Called with m.mu held.
+func (m *NonceManager) save() error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m.accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path) // Atomic replace so a crash never leaves half a file
+}
+
+func nonceKey(chainID *big.Int, address common.Address) string {
+	return chainID.String() + ":" + strings.ToLower(address.Hex())
+}
+
+// isNonceError reports whether the node rejected a transaction because its nonce is already used
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// rejected reports whether err is the node's error response to a transaction, which it then did not take.
+// A timeout or a dropped connection leaves open whether the node got the transaction.
+func rejected(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr)
+}
+
+// sendWithNonce reserves a nonce for from and calls send to sign and broadcast a transaction with it;
+// send returns the signed transaction even when broadcasting it fails. The nonce is released when signing
+// fails or the node rejects the transaction. After any other broadcast error the node may hold the
+// transaction, so the nonce stays in flight under its hash until the next sync finds it mined, pending
+// or unknown (a gap, handed out again). If the node rejects the nonce the manager resyncs and send is
+// tried once more with a fresh nonce.
+func sendWithNonce(client *ethclient.Client, chainID *big.Int, from common.Address, send func(nonce uint64) (*types.Transaction, error)) (*types.Transaction, error) {
+	ctx := context.Background()
+	for attempt := 0; ; attempt++ {
+		nonce, err := Nonces().Next(ctx, client, chainID, from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		tx, err := send(nonce)
+		if err == nil {
+			Nonces().Sent(chainID, from, nonce, tx.Hash())
+			return tx, nil
+		}
+		if tx != nil && !rejected(err) {
+			Nonces().Sent(chainID, from, nonce, tx.Hash())
+			return nil, err
+		}
+		Nonces().Failed(chainID, from, nonce)
+		if attempt > 0 || !isNonceError(err) {
+			return nil, err
+		}
+		log.Printf("nonce manager: nonce %d of %s rejected (%v), resyncing", nonce, from.Hex(), err)
+		if err := Nonces().Resync(ctx, client, chainID, from); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// AccountNonces returns the nonce manager's view of address on the active network
+// together with the node's pending nonce
+func AccountNonces(address common.Address) (NonceState, uint64, error) {
+	client, network, err := Dial()
+	if err != nil {
+		return NonceState{}, 0, err
+	}
+	defer client.Close()
+
+	pending, err := client.PendingNonceAt(context.Background(), address)
+	if err != nil {
+		return NonceState{}, 0, err
+	}
+	return Nonces().State(big.NewInt(network.ChainID), address), pending, nil
+}
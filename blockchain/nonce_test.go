@@ -0,0 +1,273 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var testChainID = big.NewInt(simChainID) // For the nonce manager, which keys accounts by chain
+
+func newTestNonces(t *testing.T) *NonceManager {
+	t.Helper()
+	m, err := NewNonceManager(filepath.Join(t.TempDir(), "nonces.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// useNonces makes m the service wide nonce manager for the rest of the test
+func useNonces(t *testing.T, m *NonceManager) {
+	noncesOnce.Do(func() {})
+	old := nonces
+	nonces = m
+	t.Cleanup(func() { nonces = old })
+}
+
+func TestNonceConcurrentHandout(t *testing.T) {
+	chain := newSimChain(t)
+	m := newTestNonces(t)
+	const n = 20
+	got := make([]uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nonce, err := m.Next(context.Background(), chain.client, testChainID, chain.accounts[0])
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got[i] = nonce
+			tx := chain.signTransfer(0, nonce, chain.accounts[1], big.NewInt(1))
+			if err := chain.client.SendTransaction(context.Background(), tx); err != nil {
+				t.Error(err)
+				return
+			}
+			m.Sent(testChainID, chain.accounts[0], nonce, tx.Hash())
+		}(i)
+	}
+	wg.Wait()
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	for i, nonce := range got {
+		if nonce != uint64(i) {
+			t.Fatalf("handed out %v, want 0..%d each once", got, n-1)
+		}
+	}
+	if state := m.State(testChainID, chain.accounts[0]); state.Next != n || len(state.InFlight) != n {
+		t.Fatalf("state %+v, want next %d with %d in flight", state, n, n)
+	}
+}
+
+func TestNonceGapReuse(t *testing.T) {
+	chain := newSimChain(t)
+	m := newTestNonces(t)
+	ctx, from := context.Background(), chain.accounts[0]
+	next := func() uint64 {
+		t.Helper()
+		nonce, err := m.Next(ctx, chain.client, testChainID, from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nonce
+	}
+
+	// The last nonce handed out is simply taken back
+	if nonce := next(); nonce != 0 {
+		t.Fatalf("first nonce %d, want 0", nonce)
+	}
+	m.Failed(testChainID, from, 0)
+	if nonce := next(); nonce != 0 {
+		t.Fatalf("nonce after the only send failed is %d, want 0", nonce)
+	}
+
+	// A failure below later nonces leaves a gap that is handed out before new nonces
+	m.Sent(testChainID, from, 0, chain.transfer(0, 0, chain.accounts[1], big.NewInt(1)).Hash())
+	second := next()
+	third := next()
+	m.Failed(testChainID, from, second)
+	m.Sent(testChainID, from, third, chain.transfer(0, third, chain.accounts[1], big.NewInt(1)).Hash()) // Queued behind the gap
+	if nonce := next(); nonce != second {
+		t.Fatalf("got nonce %d, want the gap %d", nonce, second)
+	}
+
+	// So is a nonce whose transaction the node never received
+	m.Sent(testChainID, from, second, common.HexToHash("0x1234"))
+	if nonce := next(); nonce != second {
+		t.Fatalf("got nonce %d, want %d again, its transaction is unknown to the node", nonce, second)
+	}
+	m.Sent(testChainID, from, second, chain.transfer(0, second, chain.accounts[1], big.NewInt(1)).Hash())
+	if nonce := next(); nonce != third+1 {
+		t.Fatalf("got nonce %d, want %d once the gap is filled", nonce, third+1)
+	}
+}
+
+// Resync moves past nonces the account used elsewhere and forgets the in-flight ones the node took
+func TestNonceResync(t *testing.T) {
+	chain := newSimChain(t)
+	m := newTestNonces(t)
+	ctx, from := context.Background(), chain.accounts[0]
+	nonce, err := m.Next(ctx, chain.client, testChainID, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Sent(testChainID, from, nonce, common.HexToHash("0x1234"))
+	chain.transfer(0, 0, chain.accounts[1], big.NewInt(1)) // Another process using the same account
+	chain.transfer(0, 1, chain.accounts[1], big.NewInt(1))
+
+	if err := m.Resync(ctx, chain.client, testChainID, from); err != nil {
+		t.Fatal(err)
+	}
+	if state := m.State(testChainID, from); state.Next != 2 || len(state.InFlight) != 0 {
+		t.Fatalf("state after resync %+v, want next 2 and nothing in flight", state)
+	}
+}
+
+// A send the node rejects for its nonce is retried once with a fresh nonce; other errors are not
+func TestSendWithNonceResyncs(t *testing.T) {
+	for _, msg := range []string{"nonce too low: next nonce 1, tx nonce 0", "already known", "replacement transaction underpriced"} {
+		t.Run(msg, func(t *testing.T) {
+			chain := newSimChain(t)
+			useNonces(t, newTestNonces(t))
+			from := chain.accounts[0]
+			var tried []uint64
+			tx, err := sendWithNonce(chain.client, testChainID, from, func(nonce uint64) (*types.Transaction, error) {
+				tried = append(tried, nonce)
+				if len(tried) == 1 {
+					chain.transfer(0, nonce, chain.accounts[2], big.NewInt(1)) // Took the nonce meanwhile
+					chain.backend.Commit()
+					return nil, errors.New(msg)
+				}
+				return chain.transfer(0, nonce, chain.accounts[1], big.NewInt(1)), nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tried) != 2 || tried[1] != tried[0]+1 || tx.Nonce() != tried[1] {
+				t.Fatalf("tried nonces %v, want a retry with the next one", tried)
+			}
+			state := Nonces().State(testChainID, from)
+			if state.Next != 2 || state.InFlight[1] != tx.Hash().Hex() {
+				t.Fatalf("state %+v, want next 2 with the retry in flight", state)
+			}
+		})
+	}
+
+	t.Run("other error", func(t *testing.T) {
+		chain := newSimChain(t)
+		useNonces(t, newTestNonces(t))
+		calls := 0
+		_, err := sendWithNonce(chain.client, testChainID, chain.accounts[0], func(nonce uint64) (*types.Transaction, error) {
+			calls++
+			return nil, errors.New("insufficient funds for gas * price + value")
+		})
+		if err == nil || calls != 1 {
+			t.Fatalf("got %v after %d sends, want the error after one", err, calls)
+		}
+		if state := Nonces().State(testChainID, chain.accounts[0]); state.Next != 0 || len(state.InFlight) != 0 {
+			t.Fatalf("state %+v, want the nonce released", state)
+		}
+	})
+}
+
+// A broadcast that may or may not have reached the node keeps its nonce in flight until a sync settles it;
+// only a rejection by the node releases it
+func TestSendWithNonceUncertainBroadcast(t *testing.T) {
+	chain := newSimChain(t)
+	useNonces(t, newTestNonces(t))
+	from := chain.accounts[0]
+	timeout := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+
+	// Taken by the node before the connection failed: the next send must not reuse the nonce
+	var sent *types.Transaction
+	_, err := sendWithNonce(chain.client, testChainID, from, func(nonce uint64) (*types.Transaction, error) {
+		sent = chain.transfer(0, nonce, chain.accounts[1], big.NewInt(1))
+		return sent, timeout
+	})
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("got %v, want the timeout", err)
+	}
+	if state := Nonces().State(testChainID, from); state.Next != 1 || state.InFlight[0] != sent.Hash().Hex() {
+		t.Fatalf("state %+v, want nonce 0 in flight under its hash", state)
+	}
+	tx, err := sendWithNonce(chain.client, testChainID, from, func(nonce uint64) (*types.Transaction, error) {
+		return chain.transfer(0, nonce, chain.accounts[1], big.NewInt(1)), nil
+	})
+	if err != nil || tx.Nonce() != 1 {
+		t.Fatalf("got %v, nonce %d; want nonce 1 after the one the node took", err, tx.Nonce())
+	}
+
+	// Lost before reaching the node: the next sync finds the hash unknown and hands the nonce out again
+	_, err = sendWithNonce(chain.client, testChainID, from, func(nonce uint64) (*types.Transaction, error) {
+		return chain.signTransfer(0, nonce, chain.accounts[1], big.NewInt(1)), context.DeadlineExceeded
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the deadline", err)
+	}
+	if state := Nonces().State(testChainID, from); state.Next != 3 || state.InFlight[2] == "" {
+		t.Fatalf("state %+v, want nonce 2 in flight", state)
+	}
+	if nonce, err := Nonces().Next(context.Background(), chain.client, testChainID, from); err != nil || nonce != 2 {
+		t.Fatalf("got nonce %d (%v), want the lost nonce 2 again", nonce, err)
+	}
+	Nonces().Failed(testChainID, from, 2)
+
+	// Rejected by the node: released at once
+	_, err = sendWithNonce(chain.client, testChainID, from, func(nonce uint64) (*types.Transaction, error) {
+		tx := chain.signTransfer(0, nonce, chain.accounts[1], new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1e6)))
+		return tx, chain.client.SendTransaction(context.Background(), tx)
+	})
+	if !rejected(err) {
+		t.Fatalf("got %v, want the node's rejection", err)
+	}
+	if state := Nonces().State(testChainID, from); state.Next != 2 || len(state.InFlight) != 2 {
+		t.Fatalf("state %+v, want nonce 2 released", state)
+	}
+}
+
+// Nonces in flight survive a restart; a nonce reserved but never sent becomes a gap
+func TestNoncePersistence(t *testing.T) {
+	chain := newSimChain(t)
+	path := filepath.Join(t.TempDir(), "nonces.json")
+	m, err := NewNonceManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, from := context.Background(), chain.accounts[0]
+	for i := 0; i < 3; i++ {
+		if _, err := m.Next(ctx, chain.client, testChainID, from); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sent0 := chain.transfer(0, 0, chain.accounts[1], big.NewInt(1))
+	sent2 := chain.transfer(0, 2, chain.accounts[1], big.NewInt(1))
+	m.Sent(testChainID, from, 0, sent0.Hash())
+	m.Sent(testChainID, from, 2, sent2.Hash()) // Nonce 1 stays reserved, its process "crashes" while signing
+
+	reloaded, err := NewNonceManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := reloaded.State(testChainID, from)
+	if state.Next != 3 || len(state.InFlight) != 2 || state.InFlight[0] != sent0.Hash().Hex() || state.InFlight[2] != sent2.Hash().Hex() {
+		t.Fatalf("reloaded state %+v, want next 3 with nonces 0 and 2 in flight", state)
+	}
+	nonce, err := reloaded.Next(ctx, chain.client, testChainID, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 1 {
+		t.Fatalf("got nonce %d after the reload, want the unsent 1", nonce)
+	}
+}
@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Reasons the multisig would revert a confirmation, revocation or execution, checked before anything is sent
+var (
+	ErrNotOwner               = errors.New("not an owner of the multisig")
+	ErrProposalNotFound       = errors.New("transaction index does not exist")
+	ErrAlreadyConfirmed       = errors.New("already confirmed by this owner")
+	ErrNotConfirmed           = errors.New("not confirmed by this owner")
+	ErrAlreadyExecuted        = errors.New("transaction already executed")
+	ErrNotEnoughConfirmations = errors.New("not enough confirmations")
+	ErrTimelocked             = errors.New("timelocked") // Use errors.As with *TimelockError for the time
+)
+
+// checkMultisigCall reads the multisig state that confirmTransaction, revokeConfirmation and
+// executeTransaction require (the modifiers and require statements of the contract) and reports the
+// first one that fails for owner
+func checkMultisigCall(client bind.ContractBackend, opts *bind.CallOpts, instance *contracts.Contracts, owner common.Address, txIndex uint64, method string) error {
+	isOwner, err := instance.IsOwner(opts, owner)
+	if err != nil {
+		return err
+	}
+	if !isOwner {
+		return fmt.Errorf("%w: %s", ErrNotOwner, owner.Hex())
+	}
+
+	index := new(big.Int).SetUint64(txIndex)
+	count, err := instance.GetTransactionCount(opts)
+	if err != nil {
+		return err
+	}
+	if index.Cmp(count) >= 0 {
+		return fmt.Errorf("%w: %d (the wallet has %s)", ErrProposalNotFound, txIndex, count)
+	}
+	proposal, err := instance.Transactions(opts, index)
+	if err != nil {
+		return err
+	}
+	if proposal.Executed {
+		return fmt.Errorf("%w: %d", ErrAlreadyExecuted, txIndex)
+	}
+
+	switch method {
+	case "confirmTransaction":
+		confirmed, err := instance.IsConfirmed(opts, index, owner)
+		if err != nil {
+			return err
+		}
+		if confirmed {
+			return fmt.Errorf("%w: %s on %d", ErrAlreadyConfirmed, owner.Hex(), txIndex)
+		}
+	case "revokeConfirmation":
+		confirmed, err := instance.IsConfirmed(opts, index, owner)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("%w: %s on %d", ErrNotConfirmed, owner.Hex(), txIndex)
+		}
+	case "executeTransaction":
+		required, err := instance.RequiredConfirmations(opts)
+		if err != nil {
+			return err
+		}
+		if proposal.Confirmations.Cmp(required) < 0 {
+			return fmt.Errorf("%w: %s of %s", ErrNotEnoughConfirmations, proposal.Confirmations, required)
+		}
+		return checkTimelock(client, opts, instance, txIndex)
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ProposalStatus groups proposals for the treasury view
+type ProposalStatus string
+
+const (
+	ProposalPending  ProposalStatus = "pending"  // Waiting for confirmations
+	ProposalReady    ProposalStatus = "ready"    // Enough confirmations, not executed
+	ProposalExecuted ProposalStatus = "executed" // Done
+)
+
+const maxProposalLimit = 100 // Largest page of proposals
+
+// MultisigInfo describes a deployed multisig wallet
+type MultisigInfo struct {
+	Address               string    `json:"address"`
+	Owners                []string  `json:"owners"`
+	RequiredConfirmations uint64    `json:"requiredConfirmations"`
+	Balance               string    `json:"balance"` // Wei
+	TransactionCount      uint64    `json:"transactionCount"`
+	Timelock              *Timelock `json:"timelock,omitempty"` // Absent for wallets deployed without one
+}
+
+// OwnerConfirmation is one cell of a proposal's confirmation matrix
+type OwnerConfirmation struct {
+	Owner     string `json:"owner"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// Proposal is a multisig transaction with the confirmations of every owner
+type Proposal struct {
+	Index         uint64              `json:"index"`
+	To            string              `json:"to"`
+	Value         string              `json:"value"` // Wei
+	Data          string              `json:"data"`  // Hex calldata
+	Call          *DecodedCall        `json:"call"`  // Data decoded with the ABI registry
+	Executed      bool                `json:"executed"`
+	Status        ProposalStatus      `json:"status"`
+	Confirmations uint64              `json:"confirmations"`
+	ExecutableAt  *time.Time          `json:"executableAt,omitempty"` // Ready proposals of wallets with a timelock: earliest execution
+	Owners        []OwnerConfirmation `json:"owners"`                 // In the order of the contract's owners array
+	History       []ProposalEvent     `json:"history,omitempty"`      // Single proposal reads only
+}
+
+// ProposalEvent is one entry of a proposal's history, read from the contract's event logs
+type ProposalEvent struct {
+	Event       string `json:"event"`           // submitted, confirmed, revoked or executed
+	Owner       string `json:"owner,omitempty"` // Owner who confirmed or revoked
+	BlockNumber uint64 `json:"blockNumber"`
+	TxHash      string `json:"txHash"`
+	logIndex    uint
+}
+
+// ProposalFilter selects a page of proposals, newest first. Page starts at 1.
+type ProposalFilter struct {
+	Status ProposalStatus // Empty for all
+	Page   int
+	Limit  int
+}
+
+// ProposalPage is one page of proposals and the number of proposals matching the filter
+type ProposalPage struct {
+	Total     int        `json:"total"`
+	Page      int        `json:"page"`
+	Limit     int        `json:"limit"`
+	Proposals []Proposal `json:"proposals"`
+}
+
+// multisigReader holds what every proposal read needs
+type multisigReader struct {
+	opts     *bind.CallOpts
+	instance *contracts.Contracts
+	owners   []common.Address
+	required *big.Int
+	timelock *Timelock // Nil for wallets deployed without one
+}
+
+// GetMultisig returns the owners, threshold, ETH balance and proposal count of the multisig at address
+func GetMultisig(address common.Address) (MultisigInfo, error) {
+	client, _, err := Dial()
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	instance, err := contracts.NewContracts(address, client)
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	owners, err := readOwners(opts, instance)
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+	required, err := instance.RequiredConfirmations(opts)
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+	count, err := instance.GetTransactionCount(opts)
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+	balance, err := client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+	timelock, err := readTimelock(opts, instance)
+	if err != nil {
+		return MultisigInfo{}, err
+	}
+
+	info := MultisigInfo{Address: address.Hex(), RequiredConfirmations: required.Uint64(), Balance: balance.String(), TransactionCount: count.Uint64(), Timelock: timelock}
+	for _, owner := range owners {
+		info.Owners = append(info.Owners, owner.Hex())
+	}
+	return info, nil
+}
+
+// GetProposal returns proposal index of the multisig at address with its history
+func GetProposal(address common.Address, index uint64) (Proposal, error) {
+	client, _, err := Dial()
+	if err != nil {
+		return Proposal{}, err
+	}
+	defer client.Close()
+
+	r, err := newMultisigReader(client, address)
+	if err != nil {
+		return Proposal{}, err
+	}
+	count, err := r.instance.GetTransactionCount(r.opts)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if new(big.Int).SetUint64(index).Cmp(count) >= 0 {
+		return Proposal{}, fmt.Errorf("%w: %d (the wallet has %s)", ErrProposalNotFound, index, count)
+	}
+	p, err := r.proposal(index)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if history, ok := EventIndex().proposalHistory(address, index); ok { // No log scan for indexed wallets
+		p.History = history
+		return p, nil
+	}
+	p.History, err = r.history(client, address, index)
+	if errors.Is(err, errNoDeploymentBlock) {
+		return p, nil // History stays out, like on nodes that pruned the logs
+	}
+	return p, err
+}
+
+// ListProposals returns a page of the proposals of the multisig at address, newest first. Without a
+// status filter only the requested page is read; with one every proposal is read to filter it.
+func ListProposals(address common.Address, filter ProposalFilter) (ProposalPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > maxProposalLimit {
+		filter.Limit = 20
+	}
+	page := ProposalPage{Page: filter.Page, Limit: filter.Limit, Proposals: []Proposal{}}
+
+	client, _, err := Dial()
+	if err != nil {
+		return page, err
+	}
+	defer client.Close()
+
+	r, err := newMultisigReader(client, address)
+	if err != nil {
+		return page, err
+	}
+	count, err := r.instance.GetTransactionCount(r.opts)
+	if err != nil {
+		return page, err
+	}
+	n := int(count.Int64())
+	skip := (filter.Page - 1) * filter.Limit
+
+	if filter.Status == "" {
+		page.Total = n
+		for i := n - 1 - skip; i >= 0 && len(page.Proposals) < filter.Limit; i-- {
+			p, err := r.proposal(uint64(i))
+			if err != nil {
+				return page, err
+			}
+			page.Proposals = append(page.Proposals, p)
+		}
+		return page, nil
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		p, err := r.proposal(uint64(i))
+		if err != nil {
+			return page, err
+		}
+		if p.Status != filter.Status {
+			continue
+		}
+		if page.Total >= skip && len(page.Proposals) < filter.Limit {
+			page.Proposals = append(page.Proposals, p)
+		}
+		page.Total++
+	}
+	return page, nil
+}
+
+func newMultisigReader(client bind.ContractBackend, address common.Address) (*multisigReader, error) {
+	instance, err := contracts.NewContracts(address, client)
+	if err != nil {
+		return nil, err
+	}
+	r := &multisigReader{opts: &bind.CallOpts{Context: context.Background()}, instance: instance}
+	if r.owners, err = readOwners(r.opts, instance); err != nil {
+		return nil, err
+	}
+	if r.required, err = instance.RequiredConfirmations(r.opts); err != nil {
+		return nil, err
+	}
+	if r.timelock, err = readTimelock(r.opts, instance); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// proposal reads proposal index and asks IsConfirmed for every owner
+func (r *multisigReader) proposal(index uint64) (Proposal, error) {
+	i := new(big.Int).SetUint64(index)
+	t, err := r.instance.Transactions(r.opts, i)
+	if err != nil {
+		return Proposal{}, err
+	}
+	p := Proposal{
+		Index:         index,
+		To:            t.To.Hex(),
+		Value:         t.Value.String(),
+		Data:          hexutil.Encode(t.Data),
+		Call:          ABIs().Decode(t.Data),
+		Executed:      t.Executed,
+		Confirmations: t.Confirmations.Uint64(),
+		Status:        ProposalPending,
+	}
+	switch {
+	case t.Executed:
+		p.Status = ProposalExecuted
+	case t.Confirmations.Cmp(r.required) >= 0:
+		p.Status = ProposalReady
+	}
+	if p.Status == ProposalReady && r.timelock != nil {
+		at, err := executableAt(r.opts, r.instance, index)
+		if err != nil {
+			return Proposal{}, err
+		}
+		if !at.IsZero() {
+			p.ExecutableAt = &at
+		}
+	}
+	for _, owner := range r.owners {
+		confirmed, err := r.instance.IsConfirmed(r.opts, i, owner)
+		if err != nil {
+			return Proposal{}, err
+		}
+		p.Owners = append(p.Owners, OwnerConfirmation{Owner: owner.Hex(), Confirmed: confirmed})
+	}
+	return p, nil
+}
+
+// history collects the submission, confirmations, revocations and execution of proposal index of wallet,
+// oldest first, reading the logs from the wallet's deployment on in indexerChunk ranges
+func (r *multisigReader) history(client *ethclient.Client, wallet common.Address, index uint64) ([]ProposalEvent, error) {
+	indexRule := []*big.Int{new(big.Int).SetUint64(index)}
+	var events []ProposalEvent
+	add := func(event string, owner *common.Address, raw types.Log) {
+		e := ProposalEvent{Event: event, BlockNumber: raw.BlockNumber, TxHash: raw.TxHash.Hex(), logIndex: raw.Index}
+		if owner != nil {
+			e.Owner = owner.Hex()
+		}
+		events = append(events, e)
+	}
+
+	err := scanLogs(r.opts.Context, client, wallet, func(opts *bind.FilterOpts) error {
+		submitted, err := r.instance.FilterSubmitTransaction(opts, indexRule, nil)
+		if err != nil {
+			return err
+		}
+		for submitted.Next() {
+			add("submitted", nil, submitted.Event.Raw)
+		}
+		confirmed, err := r.instance.FilterConfirmTransaction(opts, nil, indexRule)
+		if err != nil {
+			return err
+		}
+		for confirmed.Next() {
+			add("confirmed", &confirmed.Event.Owner, confirmed.Event.Raw)
+		}
+		revoked, err := r.instance.FilterRevokeConfirmation(opts, nil, indexRule)
+		if err != nil {
+			return err
+		}
+		for revoked.Next() {
+			add("revoked", &revoked.Event.Owner, revoked.Event.Raw)
+		}
+		executed, err := r.instance.FilterExecuteTransaction(opts, indexRule)
+		if err != nil {
+			return err
+		}
+		for executed.Next() {
+			add("executed", nil, executed.Event.Raw)
+		}
+		for _, err := range []error{submitted.Error(), confirmed.Error(), revoked.Error(), executed.Error()} {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].logIndex < events[j].logIndex
+	})
+	return events, nil
+}
@@ -0,0 +1,240 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Standard token interfaces, only the state-changing methods a multisig would call
+const (
+	erc20ABI = `[
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"transferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"increaseAllowance","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"addedValue","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"decreaseAllowance","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"subtractedValue","type":"uint256"}],"outputs":[{"type":"bool"}]}
+]`
+	erc721ABI = `[
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"transferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"setApprovalForAll","stateMutability":"nonpayable","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]}
+]`
+	erc1155ABI = `[
+	{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"safeBatchTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"amounts","type":"uint256[]"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"setApprovalForAll","stateMutability":"nonpayable","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]}
+]`
+)
+
+var (
+	ErrABINotFound = errors.New("abi not found")
+	ErrBuiltinABI  = errors.New("built-in abis cannot be changed")
+)
+
+// RegisteredABI describes an ABI of the registry
+type RegisteredABI struct {
+	Name    string   `json:"name"`
+	Builtin bool     `json:"builtin"`
+	Methods []string `json:"methods"` // Signatures
+}
+
+// ABIRegistry decodes calldata by its 4-byte selector. It holds the multisig's own ABI, ERC-20,
+// ERC-721 and ERC-1155 and the ABIs users upload, which are persisted as a JSON file.
+type ABIRegistry struct {
+	path     string
+	mu       sync.Mutex
+	builtins []namedABI
+	uploaded map[string]json.RawMessage // By name, as uploaded
+	parsed   map[string]abi.ABI
+}
+
+type namedABI struct {
+	name string
+	abi  abi.ABI
+}
+
+var (
+	registryOnce sync.Once
+	registry     *ABIRegistry
+)
+
+// ABIs returns the service wide ABI registry, persisted in the configured data directory
+func ABIs() *ABIRegistry {
+	registryOnce.Do(func() { registry = newABIRegistry(config.Get().ABIsFile()) })
+	return registry
+}
+
+// newABIRegistry loads the built-in ABIs and the uploads persisted at path
+func newABIRegistry(path string) *ABIRegistry {
+	r := &ABIRegistry{path: path, uploaded: map[string]json.RawMessage{}, parsed: map[string]abi.ABI{}}
+	multisig, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("multisig abi: %v", err))
+	}
+	r.builtins = append(r.builtins, namedABI{"multisig", *multisig}) // Always first, see ordered
+	for _, std := range []struct{ name, json string }{{"erc20", erc20ABI}, {"erc721", erc721ABI}, {"erc1155", erc1155ABI}} {
+		parsed, err := abi.JSON(strings.NewReader(std.json))
+		if err != nil {
+			panic(fmt.Sprintf("%s abi: %v", std.name, err))
+		}
+		r.builtins = append(r.builtins, namedABI{std.name, parsed})
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &r.uploaded)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("abi registry: %v", err)
+	}
+	for name, raw := range r.uploaded {
+		parsed, err := abi.JSON(bytes.NewReader(raw))
+		if err != nil {
+			log.Printf("abi registry: %s: %v", name, err)
+			continue
+		}
+		r.parsed[name] = parsed
+	}
+	return r
+}
+
+// Add stores an uploaded ABI under name, replacing an earlier upload with the same name
+func (r *ABIRegistry) Add(name string, raw json.RawMessage) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCall)
+	}
+	parsed, err := abi.JSON(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("%w: abi: %v", ErrInvalidCall, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.builtin(name) {
+		return fmt.Errorf("%w: %s", ErrBuiltinABI, name)
+	}
+	r.uploaded[name], r.parsed[name] = raw, parsed
+	return r.save()
+}
+
+// Remove deletes the uploaded ABI name
+func (r *ABIRegistry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.builtin(name) {
+		return fmt.Errorf("%w: %s", ErrBuiltinABI, name)
+	}
+	if _, ok := r.uploaded[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrABINotFound, name)
+	}
+	delete(r.uploaded, name)
+	delete(r.parsed, name)
+	return r.save()
+}
+
+// List returns the built-in ABIs followed by the uploaded ones
+func (r *ABIRegistry) List() []RegisteredABI {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []RegisteredABI
+	for _, a := range r.ordered() {
+		entry := RegisteredABI{Name: a.name, Builtin: r.builtin(a.name), Methods: []string{}}
+		for _, m := range a.abi.Methods {
+			entry.Methods = append(entry.Methods, m.Sig)
+		}
+		sort.Strings(entry.Methods)
+		list = append(list, entry)
+	}
+	return list
+}
+
+// Decode renders calldata with the first ABI, in List order, that has a method with its selector and
+// decodes cleanly. Other ABIs with the same method are named in Alternatives. Calldata nothing in
+// the registry decodes is marked Unknown; empty calldata is a plain ETH transfer.
+func (r *ABIRegistry) Decode(data []byte) *DecodedCall {
+	call := &DecodedCall{Data: hexutil.Encode(data)}
+	if len(data) == 0 {
+		return call
+	}
+	if len(data) < 4 {
+		call.Unknown = true
+		return call
+	}
+	call.Selector = hexutil.Encode(data[:4])
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.ordered() {
+		method, err := a.abi.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		if call.Method != "" {
+			call.Alternatives = append(call.Alternatives, a.name)
+			continue
+		}
+		decoded, err := decodeCall(method, data)
+		if err != nil {
+			continue // Selector collision with different arguments
+		}
+		decoded.ABI = a.name
+		call = decoded
+	}
+	call.Unknown = call.Method == ""
+	return call
+}
+
+// ordered lists the built-in ABIs (multisig, then the token standards), then the uploaded ABIs by name.
+// Built-ins come first so an uploaded ABI reusing a selector such as transfer or approve only shows up
+// in Alternatives instead of renaming the call. Called with r.mu held.
+func (r *ABIRegistry) ordered() []namedABI {
+	list := append([]namedABI{}, r.builtins...)
+	var names []string
+	for name := range r.parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list = append(list, namedABI{name, r.parsed[name]})
+	}
+	return list
+}
+
+func (r *ABIRegistry) builtin(name string) bool {
+	for _, a := range r.builtins {
+		if a.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// save writes the uploaded ABIs to the registry file. Called with r.mu held.
+func (r *ABIRegistry) save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r.uploaded, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path) // Atomic replace so a crash never leaves half a file
+}
@@ -0,0 +1,135 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// tokenABI reuses the ERC-20 transfer selector and adds a method of its own
+const tokenABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"recipient","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"mint","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[]}
+]`
+
+func newTestRegistry(t *testing.T) *ABIRegistry {
+	t.Helper()
+	return newABIRegistry(filepath.Join(t.TempDir(), "abis.json"))
+}
+
+func encodeCall(t *testing.T, abiJSON, method string, args ...string) []byte {
+	t.Helper()
+	spec := CallSpec{ABI: json.RawMessage(abiJSON), Method: method}
+	for _, a := range args {
+		spec.Args = append(spec.Args, json.RawMessage(a))
+	}
+	data, _, err := BuildCalldata(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRegistryDecodeBuiltins(t *testing.T) {
+	r := newTestRegistry(t)
+	for _, tc := range []struct {
+		data         []byte
+		abi, method  string
+		alternatives []string
+	}{
+		{encodeCall(t, erc20ABI, "transfer", `"`+testRecipient+`"`, `1`), "erc20", "transfer", nil},
+		// ERC-20 and ERC-721 share transferFrom(address,address,uint256); the standards keep their order
+		{encodeCall(t, erc721ABI, "transferFrom", `"`+testRecipient+`"`, `"`+testRecipient+`"`, `7`), "erc20", "transferFrom", []string{"erc721"}},
+		{encodeCall(t, erc1155ABI, "safeBatchTransferFrom", `"`+testRecipient+`"`, `"`+testRecipient+`"`, `[1]`, `[2]`, `"0x"`), "erc1155", "safeBatchTransferFrom", nil},
+		{encodeCall(t, erc721ABI, "setApprovalForAll", `"`+testRecipient+`"`, `true`), "erc721", "setApprovalForAll", []string{"erc1155"}},
+	} {
+		call := r.Decode(tc.data)
+		if call.Unknown || call.ABI != tc.abi || call.Method != tc.method || !reflect.DeepEqual(call.Alternatives, tc.alternatives) {
+			t.Errorf("%s: decoded with %s as %s (alternatives %v, unknown %v), want %s %v", tc.method, call.ABI, call.Method, call.Alternatives, call.Unknown, tc.abi, tc.alternatives)
+		}
+	}
+}
+
+// An upload reusing a built-in selector never renames the call, it only shows up as an alternative
+func TestRegistryBuiltinsBeforeUploads(t *testing.T) {
+	r := newTestRegistry(t)
+	if err := r.Add("mytoken", json.RawMessage(tokenABI)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add("another", json.RawMessage(tokenABI)); err != nil {
+		t.Fatal(err)
+	}
+
+	call := r.Decode(encodeCall(t, tokenABI, "transfer", `"`+testRecipient+`"`, `1`))
+	if call.ABI != "erc20" || call.Args[0].Name != "to" || !reflect.DeepEqual(call.Alternatives, []string{"another", "mytoken"}) {
+		t.Fatalf("decoded with %s (%+v), alternatives %v; want erc20 then the uploads by name", call.ABI, call.Args, call.Alternatives)
+	}
+
+	// A selector only uploads know is decoded with the first upload by name
+	call = r.Decode(encodeCall(t, tokenABI, "mint", `"`+testRecipient+`"`, `1`))
+	if call.Unknown || call.ABI != "another" || call.Method != "mint" || !reflect.DeepEqual(call.Alternatives, []string{"mytoken"}) {
+		t.Fatalf("decoded %+v, want mint from another", call)
+	}
+}
+
+func TestRegistryDecodeUnknown(t *testing.T) {
+	r := newTestRegistry(t)
+	for _, data := range []string{"0xdeadbeef", "0xdeadbeef0001", "0xa905"} {
+		call := r.Decode(hexutil.MustDecode(data))
+		if !call.Unknown || call.Method != "" || call.Data != data {
+			t.Errorf("%s: got %+v, want it marked unknown", data, call)
+		}
+	}
+	// A known selector whose arguments do not decode is unknown too
+	if call := r.Decode(hexutil.MustDecode("0xa9059cbb0001")); !call.Unknown {
+		t.Errorf("truncated transfer: got %+v, want it marked unknown", call)
+	}
+	// No calldata is a plain ETH transfer, nothing to review
+	if call := r.Decode(nil); call.Unknown || call.Data != "0x" {
+		t.Errorf("empty calldata: got %+v", call)
+	}
+}
+
+func TestRegistryUploads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abis.json")
+	r := newABIRegistry(path)
+	if err := r.Add("erc20", json.RawMessage(tokenABI)); !errors.Is(err, ErrBuiltinABI) {
+		t.Fatalf("replacing a built-in: got %v, want %v", err, ErrBuiltinABI)
+	}
+	if err := r.Remove("multisig"); !errors.Is(err, ErrBuiltinABI) {
+		t.Fatalf("removing a built-in: got %v, want %v", err, ErrBuiltinABI)
+	}
+	if err := r.Add("broken", json.RawMessage(`{"not": "an abi"}`)); !errors.Is(err, ErrInvalidCall) {
+		t.Fatalf("invalid abi: got %v, want %v", err, ErrInvalidCall)
+	}
+	if err := r.Remove("mytoken"); !errors.Is(err, ErrABINotFound) {
+		t.Fatalf("removing a missing abi: got %v, want %v", err, ErrABINotFound)
+	}
+	if err := r.Add("mytoken", json.RawMessage(tokenABI)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads survive a restart and are listed after the built-ins
+	reloaded := newABIRegistry(path)
+	var names []string
+	for _, a := range reloaded.List() {
+		names = append(names, a.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"multisig", "erc20", "erc721", "erc1155", "mytoken"}) {
+		t.Fatalf("listed %v", names)
+	}
+	if call := reloaded.Decode(encodeCall(t, tokenABI, "mint", `"`+testRecipient+`"`, `1`)); call.ABI != "mytoken" {
+		t.Fatalf("decoded %+v after the reload, want mytoken", call)
+	}
+
+	if err := reloaded.Remove("mytoken"); err != nil {
+		t.Fatal(err)
+	}
+	if call := newABIRegistry(path).Decode(encodeCall(t, tokenABI, "mint", `"`+testRecipient+`"`, `1`)); !call.Unknown {
+		t.Fatalf("decoded %+v after removing the abi, want unknown", call)
+	}
+}
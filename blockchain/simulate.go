@@ -0,0 +1,182 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// TxResult is the outcome of a state-changing call: the hash of the broadcast transaction or, for a
+// dry run (TxOptions.DryRun), the simulation instead
+type TxResult struct {
+	TxHash     string
+	Simulation *Simulation
+}
+
+// Simulation is a transaction run with eth_call from the acting account at the latest block, without
+// broadcasting it
+type Simulation struct {
+	Success        bool            `json:"success"`
+	RevertReason   string          `json:"revertReason,omitempty"` // Decoded Error(string) or Panic(uint256), else the raw revert data
+	Error          string          `json:"error,omitempty"`        // Why it would fail when it is not a revert, e.g. funds for the fee
+	From           string          `json:"from"`
+	To             string          `json:"to,omitempty"` // Empty for contract creation
+	Value          string          `json:"value"`        // Wei
+	BlockNumber    uint64          `json:"blockNumber"`
+	GasEstimate    uint64          `json:"gasEstimate,omitempty"` // Gas the node expects the call to use
+	GasLimit       uint64          `json:"gasLimit,omitempty"`    // Limit it would be sent with (margin or override)
+	Fee            string          `json:"fee,omitempty"`         // Expected fee in wei: gasEstimate at the next base fee plus tip
+	MaxFee         string          `json:"maxFee,omitempty"`      // Most the fee can be: gasLimit at maxFeePerGas
+	BalanceChanges []BalanceChange `json:"balanceChanges"`
+}
+
+// BalanceChange is the expected ETH balance change of one account
+type BalanceChange struct {
+	Address string `json:"address"`
+	Before  string `json:"before"` // Wei at the simulated block
+	Change  string `json:"change"` // Negative when ETH leaves the account
+	After   string `json:"after"`
+}
+
+// ethTransfer is a direct ETH movement of a call
+type ethTransfer struct {
+	from, to common.Address
+	amount   *big.Int
+}
+
+// simulate runs the transaction sendTx would send with eth_call and prices it like sendTx would.
+// Only a failing RPC is an error; a revert or missing funds is reported in the Simulation.
+func simulate(ctx context.Context, client *ethclient.Client, from common.Address, to *common.Address, value *big.Int, data []byte, opts TxOptions) (*Simulation, error) {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	block := head.Number
+	sim := &Simulation{From: from.Hex(), Value: value.String(), BlockNumber: block.Uint64(), BalanceChanges: []BalanceChange{}}
+	if to != nil {
+		sim.To = to.Hex()
+	}
+
+	msg := ethereum.CallMsg{From: from, To: to, Value: value, Data: data, Gas: opts.GasLimit}
+	if _, err := client.CallContract(ctx, msg, block); err != nil {
+		var revert *RevertError
+		if !errors.As(DecodeRevert(err), &revert) {
+			sim.Error = err.Error()
+			return sim, nil
+		}
+		sim.RevertReason = revert.Reason
+		if sim.RevertReason == "" {
+			sim.RevertReason = revert.Data
+		}
+		return sim, nil
+	}
+
+	fees, err := SuggestFees(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	msg.Gas = 0
+	if sim.GasEstimate, err = client.EstimateGas(ctx, msg); err != nil {
+		sim.Error = err.Error()
+		return sim, nil
+	}
+	if sim.GasLimit, err = EstimateGas(ctx, client, msg, opts.GasLimit); err != nil {
+		sim.Error = err.Error()
+		return sim, nil
+	}
+	price := fees.GasPrice
+	if !fees.Legacy {
+		price = capped(new(big.Int).Add(fees.BaseFee, fees.GasTipCap), fees.GasFeeCap)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(sim.GasEstimate), price)
+	sim.Fee = fee.String()
+	sim.MaxFee = new(big.Int).Mul(new(big.Int).SetUint64(sim.GasLimit), fees.MaxGasPrice()).String()
+
+	transfers := []ethTransfer{{from: from, amount: fee}} // No recipient: burnt and paid to the block producer
+	if to != nil {
+		if value.Sign() > 0 {
+			transfers = append(transfers, ethTransfer{from, *to, value})
+		}
+		inner, err := innerTransfers(ctx, client, *to, data, block)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, inner...)
+	}
+	if sim.BalanceChanges, err = balanceChanges(ctx, client, transfers, block); err != nil {
+		return nil, err
+	}
+
+	if err := checkBalance(ctx, client, from, value, sim.GasLimit, fees); err != nil {
+		sim.Error = err.Error()
+		return sim, nil
+	}
+	sim.Success = true
+	return sim, nil
+}
+
+// innerTransfers returns the ETH a multisig call moves on: executeTransaction pays the proposal's
+// value from the wallet to its target
+func innerTransfers(ctx context.Context, client *ethclient.Client, wallet common.Address, data []byte, block *big.Int) ([]ethTransfer, error) {
+	parsed, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	execute := parsed.Methods["executeTransaction"]
+	if len(data) < 4 || !bytes.Equal(data[:4], execute.ID) {
+		return nil, nil
+	}
+	args, err := execute.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil
+	}
+	instance, err := contracts.NewContracts(wallet, client)
+	if err != nil {
+		return nil, err
+	}
+	t, err := instance.Transactions(&bind.CallOpts{Context: ctx, BlockNumber: block}, args[0].(*big.Int))
+	if err != nil {
+		return nil, err
+	}
+	if t.Value.Sign() == 0 {
+		return nil, nil
+	}
+	return []ethTransfer{{wallet, t.To, t.Value}}, nil
+}
+
+// balanceChanges sums transfers per account, in the order the accounts first appear
+func balanceChanges(ctx context.Context, client *ethclient.Client, transfers []ethTransfer, block *big.Int) ([]BalanceChange, error) {
+	var order []common.Address
+	changes := map[common.Address]*big.Int{}
+	add := func(account common.Address, amount *big.Int) {
+		if changes[account] == nil {
+			changes[account] = new(big.Int)
+			order = append(order, account)
+		}
+		changes[account].Add(changes[account], amount)
+	}
+	for _, t := range transfers {
+		add(t.from, new(big.Int).Neg(t.amount))
+		if t.to != (common.Address{}) {
+			add(t.to, t.amount)
+		}
+	}
+
+	list := []BalanceChange{}
+	for _, account := range order {
+		before, err := client.BalanceAt(ctx, account, block)
+		if err != nil {
+			return nil, err
+		}
+		after := new(big.Int).Add(before, changes[account])
+		list = append(list, BalanceChange{Address: account.Hex(), Before: before.String(), Change: changes[account].String(), After: after.String()})
+	}
+	return list, nil
+}
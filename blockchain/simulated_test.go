@@ -0,0 +1,125 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const simChainID = 1337 // Chain ID of every simulated backend
+
+// simChain is a simulated chain served over HTTP, so code that dials the active network talks to it
+type simChain struct {
+	t        *testing.T
+	backend  *simulated.Backend
+	client   *ethclient.Client
+	url      string
+	keys     []*ecdsa.PrivateKey
+	accounts []common.Address
+}
+
+// newSimChain starts a simulated chain with three funded accounts and makes it the active network,
+// with the service state in a temporary directory
+func newSimChain(t *testing.T) *simChain {
+	t.Helper()
+	c := &simChain{t: t}
+	alloc := types.GenesisAlloc{}
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.keys = append(c.keys, key)
+		c.accounts = append(c.accounts, crypto.PubkeyToAddress(key.PublicKey))
+		alloc[c.accounts[i]] = types.Account{Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0") // Find a free port for the node's HTTP endpoint
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	c.backend = simulated.NewBackend(alloc, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.HTTPHost, nodeConf.HTTPPort = "127.0.0.1", port
+		nodeConf.HTTPModules = []string{"eth", "net", "web3"}
+	})
+	t.Cleanup(func() { c.backend.Close() })
+
+	c.url = "http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	if c.client, err = ethclient.Dial(c.url); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.client.Close)
+	c.backend.Commit() // The transaction indexer only starts with a new head
+	c.waitIndexed()
+	useConfig(t, c.url)
+	return c
+}
+
+// waitIndexed waits for the node's transaction indexer, which answers every lookup with an error until it has run once
+func (c *simChain) waitIndexed() {
+	c.t.Helper()
+	for i := 0; ; i++ {
+		_, err := c.client.TransactionReceipt(context.Background(), common.Hash{})
+		if errors.Is(err, ethereum.NotFound) {
+			return
+		}
+		if i == 100 {
+			c.t.Fatalf("transaction indexer did not start: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// useConfig installs a devnet config pointing at url with its data directory in a temporary directory
+func useConfig(t *testing.T, url string) *config.Config {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	devnet := cfg.Networks["devnet"]
+	devnet.RPCURLs = []string{url}
+	cfg.Networks["devnet"] = devnet
+	config.Set(cfg)
+	t.Cleanup(func() { config.Set(nil) })
+	return cfg
+}
+
+// transfer signs a transfer of value from account i with nonce and sends it to the node without mining it
+func (c *simChain) transfer(i int, nonce uint64, to common.Address, value *big.Int) *types.Transaction {
+	c.t.Helper()
+	tx := c.signTransfer(i, nonce, to, value)
+	if err := c.client.SendTransaction(context.Background(), tx); err != nil {
+		c.t.Fatal(err)
+	}
+	return tx
+}
+
+// signTransfer signs a transfer of value from account i with nonce
+func (c *simChain) signTransfer(i int, nonce uint64, to common.Address, value *big.Int) *types.Transaction {
+	c.t.Helper()
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID: big.NewInt(simChainID), Nonce: nonce, To: &to, Value: value, Gas: 21000,
+		GasTipCap: big.NewInt(params.GWei), GasFeeCap: big.NewInt(100 * params.GWei),
+	}), types.LatestSignerForChainID(big.NewInt(simChainID)), c.keys[i])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return tx
+}
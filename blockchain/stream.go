@@ -0,0 +1,277 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// streamedEvents are the events an EventStream delivers
+var streamedEvents = map[string]bool{"deposit": true, "submitted": true, "confirmed": true, "revoked": true, "executed": true}
+
+// EventPosition is the place of an event in the chain, to resume a stream from
+type EventPosition struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	LogIndex    uint   `json:"logIndex"`
+}
+
+func (p EventPosition) before(q EventPosition) bool {
+	return p.BlockNumber < q.BlockNumber || (p.BlockNumber == q.BlockNumber && p.LogIndex < q.LogIndex)
+}
+
+// previous is the position right before p
+func (p EventPosition) previous() EventPosition {
+	if p.LogIndex == 0 {
+		return EventPosition{p.BlockNumber - 1, math.MaxUint}
+	}
+	return EventPosition{p.BlockNumber, p.LogIndex - 1}
+}
+
+func (e ChainEvent) position() EventPosition {
+	return EventPosition{e.BlockNumber, e.LogIndex}
+}
+
+// EventStream delivers the Deposit, SubmitTransaction, ConfirmTransaction, RevokeConfirmation and
+// ExecuteTransaction events of one wallet as they happen, in chain order. Events come from one log
+// subscription on the wallet, or from polling the logs when the node offers no subscriptions. An event
+// that a reorg removes is delivered again with Removed set; when polling, that may include events of
+// the blocks between the last polled head that is still canonical and the fork, which follow again.
+type EventStream struct {
+	events chan ChainEvent
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	err    error
+}
+
+// StreamEvents starts streaming the events of wallet. With from set, the events at and after from
+// are delivered first, so a client can pick up where it was disconnected; otherwise the stream starts
+// with the next block.
+func StreamEvents(wallet common.Address, from *EventPosition) (*EventStream, error) {
+	client, err := dialEvents()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	code, err := client.CodeAt(ctx, wallet, nil)
+	if err == nil && len(code) == 0 {
+		err = fmt.Errorf("%w: %s", bind.ErrNoCode, wallet.Hex())
+	}
+	var filterer *contracts.ContractsFilterer
+	if err == nil {
+		filterer, err = contracts.NewContractsFilterer(wallet, client)
+	}
+	if err != nil {
+		cancel()
+		client.Close()
+		return nil, err
+	}
+
+	s := &EventStream{events: make(chan ChainEvent), cancel: cancel}
+	logs := make(chan types.Log, 64)
+	sub, err := client.SubscribeFilterLogs(ctx, streamQuery(wallet), logs) // Before reading the head, so no block falls in between
+	if err != nil {
+		sub = nil // Polling instead
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		cancel()
+		client.Close()
+		return nil, err
+	}
+	go s.run(ctx, client, filterer, wallet, from, head, sub, logs)
+	return s, nil
+}
+
+// Events is closed when the stream ends; Err then tells why
+func (s *EventStream) Events() <-chan ChainEvent { return s.events }
+
+// Err returns the error that ended the stream, nil while it runs or after Close
+func (s *EventStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the stream
+func (s *EventStream) Close() { s.cancel() }
+
+// streamQuery selects the logs of wallet's streamed events. A single filter keeps them in the order of
+// the chain, which separate subscriptions per event do not.
+func streamQuery(wallet common.Address) ethereum.FilterQuery {
+	parsed, _ := contracts.ContractsMetaData.GetAbi() // Parsed once by the bindings already
+	var topics []common.Hash
+	for _, name := range []string{"Deposit", "SubmitTransaction", "ConfirmTransaction", "RevokeConfirmation", "ExecuteTransaction"} {
+		topics = append(topics, parsed.Events[name].ID)
+	}
+	return ethereum.FilterQuery{Addresses: []common.Address{wallet}, Topics: [][]common.Hash{topics}}
+}
+
+// run replays the missed events, then delivers live ones until the stream is closed or fails
+func (s *EventStream) run(ctx context.Context, client *ethclient.Client, f *contracts.ContractsFilterer, wallet common.Address, from *EventPosition, head uint64, sub ethereum.Subscription, logs <-chan types.Log) {
+	defer client.Close()
+	defer close(s.events)
+	defer s.cancel()
+	if sub != nil {
+		defer sub.Unsubscribe()
+	}
+
+	sent := EventPosition{BlockNumber: head, LogIndex: math.MaxUint} // Everything up to the head is replayed or not wanted
+	if from != nil {
+		if _, err := s.replay(ctx, client, wallet, *from, head); err != nil {
+			s.fail(err)
+			return
+		}
+	}
+	if sub == nil {
+		s.fail(s.poll(ctx, client, wallet, head))
+		return
+	}
+
+	times := map[common.Hash]time.Time{}
+	for {
+		select {
+		case raw := <-logs:
+			e, err := chainEvent(ctx, client, f, raw, times)
+			if err != nil {
+				s.fail(err)
+				return
+			}
+			switch {
+			case e == nil:
+				continue
+			case e.Removed: // Let the events of the replacing block through, even at the same position
+				if p := e.position().previous(); p.before(sent) {
+					sent = p
+				}
+			case !sent.before(e.position()):
+				continue // Replayed already
+			default:
+				sent = e.position()
+			}
+			if !s.send(ctx, *e) {
+				return
+			}
+		case err := <-sub.Err():
+			s.fail(err)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// replay delivers the streamed events from position from up to block head and returns them. It stops
+// early without an error when the stream is closed.
+func (s *EventStream) replay(ctx context.Context, client *ethclient.Client, wallet common.Address, from EventPosition, head uint64) ([]ChainEvent, error) {
+	var sent []ChainEvent
+	for start := from.BlockNumber; start <= head; start += indexerChunk {
+		events, err := fetchEvents(ctx, client, wallet, start, min(start+indexerChunk-1, head))
+		if err != nil {
+			return sent, err
+		}
+		for _, e := range events {
+			if !streamedEvents[e.Event] || e.position().before(from) {
+				continue
+			}
+			if !s.send(ctx, e) {
+				return sent, nil
+			}
+			sent = append(sent, e)
+		}
+	}
+	return sent, nil
+}
+
+// poll delivers the streamed events after block head every indexerPollInterval. Like the indexer it
+// remembers the polled heads: when a reorg replaced them, the events delivered after the newest head
+// still canonical are delivered again with Removed set, newest first, and those blocks are read again.
+func (s *EventStream) poll(ctx context.Context, client *ethclient.Client, wallet common.Address, head uint64) error {
+	ticker := time.NewTicker(indexerPollInterval)
+	defer ticker.Stop()
+	start, next := head+1, head+1
+	var heads []blockRef
+	if header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(head)); err == nil {
+		heads = append(heads, blockRef{head, header.Hash().Hex()})
+	}
+	var delivered []ChainEvent // After the oldest remembered head
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		ancestor, reorged, err := forkPoint(ctx, client, heads)
+		if err != nil {
+			return err
+		}
+		if reorged {
+			for len(delivered) > 0 && delivered[len(delivered)-1].BlockNumber > ancestor {
+				e := delivered[len(delivered)-1]
+				e.Removed = true
+				if !s.send(ctx, e) {
+					return nil
+				}
+				delivered = delivered[:len(delivered)-1]
+			}
+			for len(heads) > 0 && heads[len(heads)-1].Number > ancestor {
+				heads = heads[:len(heads)-1]
+			}
+			next = max(min(next, ancestor+1), start)
+		}
+
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		latest := header.Number.Uint64()
+		if latest < next {
+			continue
+		}
+		sent, err := s.replay(ctx, client, wallet, EventPosition{BlockNumber: next}, latest)
+		if err != nil {
+			return err
+		}
+		delivered = append(delivered, sent...)
+		next = latest + 1
+		heads = append(heads, blockRef{latest, header.Hash().Hex()})
+		if len(heads) > indexerReorgDepth {
+			heads = heads[len(heads)-indexerReorgDepth:]
+		}
+		for len(delivered) > 0 && delivered[0].BlockNumber <= heads[0].Number-min(heads[0].Number, indexerReorgDepth) {
+			delivered = delivered[1:]
+		}
+	}
+}
+
+// send delivers e, reporting false when the stream was closed
+func (s *EventStream) send(ctx context.Context, e ChainEvent) bool {
+	select {
+	case s.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// fail records err as the reason the stream ended, unless it only says the stream was closed
+func (s *EventStream) fail(err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
@@ -0,0 +1,86 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// ErrInvalidTimelock rejects a timelock change before it is proposed
+var ErrInvalidTimelock = errors.New("invalid timelock")
+
+// Timelock is a wallet's cooling-off period between a proposal reaching the threshold and its execution
+type Timelock struct {
+	Delay    uint64 `json:"delay"`    // Seconds
+	MinValue string `json:"minValue"` // Wei; plain transfers of less execute without delay (calls with data always wait), 0 for none
+}
+
+// TimelockError is an execution refused because the proposal's timelock has not passed yet
+type TimelockError struct {
+	Index        uint64
+	ExecutableAt time.Time
+}
+
+func (e *TimelockError) Error() string {
+	return fmt.Sprintf("proposal %d is timelocked until %s", e.Index, e.ExecutableAt.Format(time.RFC3339))
+}
+
+func (e *TimelockError) Unwrap() error { return ErrTimelocked }
+
+// readTimelock reads the timelock settings of a wallet, nil for wallets deployed before the contract
+// had one (the call reverts)
+func readTimelock(opts *bind.CallOpts, instance *contracts.Contracts) (*Timelock, error) {
+	delay, err := instance.TimelockDelay(opts)
+	if err != nil {
+		var revert *RevertError
+		if errors.As(DecodeRevert(err), &revert) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	minValue, err := instance.TimelockMinValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Timelock{Delay: delay.Uint64(), MinValue: minValue.String()}, nil
+}
+
+// executableAt returns the earliest time proposal index can be executed, zero while it lacks confirmations
+func executableAt(opts *bind.CallOpts, instance *contracts.Contracts, index uint64) (time.Time, error) {
+	at, err := instance.ExecutableAt(opts, new(big.Int).SetUint64(index))
+	if err != nil || at.Sign() == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(at.Int64(), 0).UTC(), nil
+}
+
+// checkTimelock reports proposal index as timelocked while the latest block is older than its
+// executableAt, which is what the contract compares block.timestamp with
+func checkTimelock(client bind.ContractBackend, opts *bind.CallOpts, instance *contracts.Contracts, index uint64) error {
+	timelock, err := readTimelock(opts, instance)
+	if err != nil || timelock == nil {
+		return err
+	}
+	at, err := executableAt(opts, instance, index)
+	if err != nil || at.IsZero() {
+		return err
+	}
+	head, err := client.HeaderByNumber(opts.Context, nil)
+	if err != nil {
+		return err
+	}
+	if head.Time < uint64(at.Unix()) {
+		return &TimelockError{Index: index, ExecutableAt: at}
+	}
+	return nil
+}
+
+// EncodeChangeTimelock encodes the wallet's changeTimelock(delay, minValue) call. minValue only exempts
+// plain ETH transfers below it: proposals with calldata, such as token transfers, always wait the delay.
+func EncodeChangeTimelock(delay uint64, minValue *big.Int) ([]byte, error) {
+	return packMultisig("changeTimelock", new(big.Int).SetUint64(delay), minValue)
+}
@@ -0,0 +1,357 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// TxStatus is where a sent transaction is in its lifecycle
+type TxStatus string
+
+const (
+	TxPending  TxStatus = "pending"  // Broadcast, no receipt yet
+	TxMined    TxStatus = "mined"    // Included and succeeded
+	TxFailed   TxStatus = "failed"   // Included but reverted
+	TxDropped  TxStatus = "dropped"  // Gone from the node's pool while its nonce is still unused
+	TxReplaced TxStatus = "replaced" // Another transaction from the same account took its nonce
+)
+
+// TxStatuses lists the valid statuses, for filters
+var TxStatuses = []TxStatus{TxPending, TxMined, TxFailed, TxDropped, TxReplaced}
+
+const (
+	txPollInterval = 3 * time.Second
+	txDropAfter    = 5 * time.Minute // Unknown to the node for this long means dropped
+)
+
+var ErrTxNotFound = errors.New("transaction not tracked")
+
+// TrackedTx is a transaction sent by the service, with the request that sent it and its latest status
+type TrackedTx struct {
+	Hash              string          `json:"hash"`
+	Purpose           string          `json:"purpose"` // transfer, deploy, submit, confirm, revoke or execute
+	Status            TxStatus        `json:"status"`
+	ChainID           int64           `json:"chainId"`
+	From              string          `json:"from"`
+	To                string          `json:"to,omitempty"` // Empty for contract creation
+	Nonce             uint64          `json:"nonce"`
+	Value             string          `json:"value"`             // Wei
+	Request           json.RawMessage `json:"request,omitempty"` // Payload of the request that sent it
+	Depth             uint64          `json:"depth"`             // Confirmations after which mined or failed is final
+	BlockNumber       uint64          `json:"blockNumber,omitempty"`
+	Confirmations     uint64          `json:"confirmations"`
+	GasUsed           uint64          `json:"gasUsed,omitempty"`
+	EffectiveGasPrice string          `json:"effectiveGasPrice,omitempty"`
+	ReplacedBy        string          `json:"replacedBy,omitempty"` // Hash of the transaction that took the nonce, when it was ours
+	Error             string          `json:"error,omitempty"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+	LastSeen          time.Time       `json:"lastSeen"` // Last time the node knew the transaction
+}
+
+// Final reports whether the status can no longer change
+func (t TrackedTx) Final() bool {
+	switch t.Status {
+	case TxDropped, TxReplaced:
+		return true
+	case TxMined, TxFailed:
+		return t.Confirmations >= t.Depth // Until then a reorg may still send it back to pending
+	}
+	return false
+}
+
+// TxFilter selects tracked transactions; empty fields match everything
+type TxFilter struct {
+	Account string   // Sender address
+	Status  TxStatus // One of TxStatuses
+}
+
+// Tracker records every transaction the service sends and follows it in the background until its
+// status is final. The records are persisted as a JSON file.
+type Tracker struct {
+	path  string
+	mu    sync.Mutex
+	txs   map[string]*TrackedTx // By lowercase hash
+	dirty bool                  // Changed since the last write
+	start sync.Once             // Starts the poller
+}
+
+var (
+	trackerOnce sync.Once
+	tracker     *Tracker
+)
+
+// Transactions returns the service wide transaction tracker, persisted in the configured data directory
+func Transactions() *Tracker {
+	trackerOnce.Do(func() { tracker = newTracker(config.Get().TransactionsFile()) })
+	return tracker
+}
+
+// newTracker loads the transactions persisted at path. Nobody watched the node's pool while the service
+// was down, so the drop timer of every open transaction starts again.
+func newTracker(path string) *Tracker {
+	t := &Tracker{path: path, txs: map[string]*TrackedTx{}}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &t.txs)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("transactions: %v", err)
+	}
+	now := time.Now().UTC()
+	for _, tx := range t.txs {
+		if !tx.Final() {
+			tx.LastSeen = now
+		}
+	}
+	return t
+}
+
+// Resume starts following the transactions that were not final when the service stopped
+func (t *Tracker) Resume() {
+	t.start.Do(func() { go t.run() })
+}
+
+// Get returns a copy of the transaction with hash
+func (t *Tracker) Get(hash string) (TrackedTx, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tx, ok := t.txs[strings.ToLower(hash)]
+	if !ok {
+		return TrackedTx{}, ErrTxNotFound
+	}
+	return *tx, nil
+}
+
+// List returns the transactions matching filter, newest first
+func (t *Tracker) List(filter TxFilter) []TrackedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	list := []TrackedTx{}
+	for _, tx := range t.txs {
+		if filter.Account != "" && !strings.EqualFold(tx.From, filter.Account) {
+			continue
+		}
+		if filter.Status != "" && tx.Status != filter.Status {
+			continue
+		}
+		list = append(list, *tx)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list
+}
+
+// track records a transaction just broadcast from from, together with the request that sent it
+func (t *Tracker) track(signedTx *types.Transaction, chainID *big.Int, from common.Address, purpose string, request interface{}) {
+	now := time.Now().UTC()
+	tx := &TrackedTx{
+		Hash:      strings.ToLower(signedTx.Hash().Hex()),
+		Purpose:   purpose,
+		Status:    TxPending,
+		ChainID:   chainID.Int64(),
+		From:      from.Hex(),
+		Nonce:     signedTx.Nonce(),
+		Value:     signedTx.Value().String(),
+		Depth:     1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		LastSeen:  now,
+	}
+	if signedTx.To() != nil {
+		tx.To = signedTx.To().Hex()
+	}
+	if network, err := config.Get().Active(); err == nil && network.Confirmations > 0 {
+		tx.Depth = network.Confirmations
+	}
+	if request != nil {
+		if data, err := json.Marshal(request); err == nil {
+			tx.Request = data
+		}
+	}
+
+	t.mu.Lock()
+	t.txs[tx.Hash] = tx
+	t.save()
+	t.mu.Unlock()
+	t.Resume()
+}
+
+// update applies change to the transaction with hash. Only a new status, block or confirmation count
+// needs writing; the poller writes once per round with flush. LastSeen alone is not written, newTracker
+// resets it on load.
+func (t *Tracker) update(hash string, change func(tx *TrackedTx)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tx := t.txs[hash]
+	before := *tx
+	change(tx)
+	if tx.Status != before.Status || tx.Confirmations != before.Confirmations || tx.BlockNumber != before.BlockNumber {
+		tx.UpdatedAt = time.Now().UTC()
+		t.dirty = true
+	}
+}
+
+// flush writes the transactions if a poll round changed any of them
+func (t *Tracker) flush() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.dirty {
+		t.save()
+	}
+}
+
+// run polls the node for every transaction that is not final yet
+func (t *Tracker) run() {
+	ticker := time.NewTicker(txPollInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		var open []TrackedTx
+		t.mu.Lock()
+		for _, tx := range t.txs {
+			if !tx.Final() {
+				open = append(open, *tx)
+			}
+		}
+		t.mu.Unlock()
+		if len(open) == 0 {
+			continue
+		}
+
+		client, network, err := Dial()
+		if err != nil {
+			log.Printf("transactions: %v", err)
+			continue
+		}
+		for _, tx := range open {
+			if tx.ChainID == network.ChainID { // Transactions of other networks wait until one is active again
+				t.check(context.Background(), client, tx)
+			}
+		}
+		client.Close()
+		t.flush()
+	}
+}
+
+// check updates tx from the node. A receipt makes it mined or failed; without one the transaction is
+// pending while the node still knows it, replaced once its nonce is used and dropped when the node
+// has forgotten it for txDropAfter.
+func (t *Tracker) check(ctx context.Context, client *ethclient.Client, tx TrackedTx) {
+	hash := common.HexToHash(tx.Hash)
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err == nil {
+		t.mined(ctx, client, tx.Hash, receipt)
+		return
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		log.Printf("transaction %s: %v", tx.Hash, err)
+		return
+	}
+
+	_, _, err = client.TransactionByHash(ctx, hash)
+	if err == nil { // Still in the pool (or back in it after a reorg)
+		t.update(tx.Hash, func(tx *TrackedTx) {
+			tx.Status, tx.BlockNumber, tx.Confirmations, tx.LastSeen = TxPending, 0, 0, time.Now().UTC()
+		})
+		return
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		log.Printf("transaction %s: %v", tx.Hash, err)
+		return
+	}
+
+	mined, err := client.NonceAt(ctx, common.HexToAddress(tx.From), nil)
+	if err != nil {
+		log.Printf("transaction %s: %v", tx.Hash, err)
+		return
+	}
+	if mined > tx.Nonce {
+		if receipt, err := client.TransactionReceipt(ctx, hash); err == nil { // Mined between the two lookups
+			t.mined(ctx, client, tx.Hash, receipt)
+			return
+		}
+		replacedBy := t.withNonce(tx)
+		t.update(tx.Hash, func(tx *TrackedTx) {
+			tx.Status, tx.BlockNumber, tx.Confirmations, tx.ReplacedBy = TxReplaced, 0, 0, replacedBy
+		})
+		return
+	}
+	if time.Since(tx.LastSeen) > txDropAfter {
+		t.update(tx.Hash, func(tx *TrackedTx) {
+			tx.Status, tx.Error = TxDropped, "the node no longer knows the transaction and its nonce is unused"
+		})
+		return
+	}
+	if tx.Status == TxMined || tx.Status == TxFailed {
+		t.update(tx.Hash, func(tx *TrackedTx) { tx.Status, tx.BlockNumber, tx.Confirmations = TxPending, 0, 0 }) // Reorged out
+	}
+}
+
+// mined records the receipt of the transaction with hash
+func (t *Tracker) mined(ctx context.Context, client *ethclient.Client, hash string, receipt *types.Receipt) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		log.Printf("transaction %s: %v", hash, err)
+		return
+	}
+	var confirmations uint64
+	if head >= receipt.BlockNumber.Uint64() {
+		confirmations = head - receipt.BlockNumber.Uint64() + 1
+	}
+	t.update(hash, func(tx *TrackedTx) {
+		tx.Status, tx.Error = TxMined, ""
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			tx.Status, tx.Error = TxFailed, "reverted"
+		}
+		tx.BlockNumber, tx.Confirmations, tx.GasUsed = receipt.BlockNumber.Uint64(), confirmations, receipt.GasUsed
+		if receipt.EffectiveGasPrice != nil {
+			tx.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+		}
+		tx.LastSeen = time.Now().UTC()
+	})
+}
+
+// withNonce returns the hash of another tracked transaction of tx's account and nonce that was mined, if any
+func (t *Tracker) withNonce(tx TrackedTx) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for hash, other := range t.txs {
+		if hash != tx.Hash && other.ChainID == tx.ChainID && strings.EqualFold(other.From, tx.From) &&
+			other.Nonce == tx.Nonce && (other.Status == TxMined || other.Status == TxFailed) {
+			return hash
+		}
+	}
+	return ""
+}
+
+// save writes the transactions to the tracker file. Called with t.mu held.
+func (t *Tracker) save() {
+	t.dirty = false
+	data, err := json.MarshalIndent(t.txs, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(t.path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(t.path+".tmp", data, 0600)
+	}
+	if err == nil {
+		err = os.Rename(t.path+".tmp", t.path) // Atomic replace so a crash never leaves half a file
+	}
+	if err != nil {
+		log.Printf("transactions: %v", err)
+	}
+}
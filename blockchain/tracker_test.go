@@ -0,0 +1,39 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// A restart starts the drop timer of open transactions again, so one failed lookup right after it
+// does not drop a transaction that was pending all along
+func TestTrackerLoadResetsLastSeen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.json")
+	stale := time.Now().UTC().Add(-time.Hour)
+	saved := map[string]*TrackedTx{
+		"0x01": {Hash: "0x01", Status: TxPending, LastSeen: stale},
+		"0x02": {Hash: "0x02", Status: TxMined, Depth: 2, Confirmations: 1, LastSeen: stale},
+		"0x03": {Hash: "0x03", Status: TxDropped, LastSeen: stale},
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newTracker(path)
+	for hash, open := range map[string]bool{"0x01": true, "0x02": true, "0x03": false} {
+		tx, err := loaded.Get(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reset := time.Since(tx.LastSeen) < txDropAfter; reset != open {
+			t.Fatalf("%s (%s) last seen %s ago, want it reset only for open transactions", hash, tx.Status, time.Since(tx.LastSeen))
+		}
+	}
+}
@@ -0,0 +1,598 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DeliveryStatus is where the delivery of one event to one webhook is
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending" // Not accepted by the receiver yet, retried with backoff
+	DeliveryFailed  DeliveryStatus = "failed"  // Gave up after webhookAttempts, kept as a dead letter
+)
+
+const (
+	webhookAttempts    = 8
+	webhookRetryDelay  = 5 * time.Second // Doubled after every failed attempt
+	webhookMaxDelay    = 30 * time.Minute
+	webhookTimeout     = 10 * time.Second
+	webhookReconnect   = 5 * time.Second
+	webhookDeadLetters = 100             // Kept per webhook, the oldest are dropped
+	webhookSaveDelay   = 1 * time.Second // Delivery progress is written at most this often
+)
+
+var (
+	ErrInvalidWebhook   = errors.New("invalid webhook")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("dead letter not found")
+)
+
+// Webhook is a callback URL that receives the events of a wallet
+type Webhook struct {
+	ID        string    `json:"id"`
+	Wallet    string    `json:"wallet"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`           // Delivered event names
+	Secret    string    `json:"secret,omitempty"` // HMAC-SHA256 key of the signatures, only returned on creation
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookRequest registers a webhook
+type WebhookRequest struct {
+	Wallet string   `json:"wallet"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // Empty for all streamed events
+	Secret string   `json:"secret"` // Generated when empty
+}
+
+// Delivery is one event on its way to one webhook
+type Delivery struct {
+	ID          string         `json:"id"`
+	Webhook     string         `json:"webhook"`
+	Sequence    uint64         `json:"sequence"` // Order of delivery to the webhook
+	Event       ChainEvent     `json:"event"`
+	Status      DeliveryStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	Error       string         `json:"error,omitempty"` // Last failure
+	NextAttempt *time.Time     `json:"nextAttempt,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook
+type WebhookPayload struct {
+	Delivery string     `json:"delivery"` // The same on every attempt, for the receiver to drop duplicates
+	Webhook  string     `json:"webhook"`
+	Event    ChainEvent `json:"event"`
+}
+
+// WebhookRegistry delivers the streamed events of wallets to the webhooks registered for them. Every
+// wallet with webhooks has one EventStream, resumed after a restart from the position after the last
+// event it handed out. Each webhook gets its events one at a time in chain order: a failed delivery
+// is retried with exponential backoff, holding back the later ones, until it ends up in a dead-letter
+// list from where it can be replayed. Everything is persisted as a JSON file.
+type WebhookRegistry struct {
+	path       string
+	mu         sync.Mutex
+	state      webhookState
+	streams    map[string]context.CancelFunc // By lowercase wallet address
+	workers    map[string]chan struct{}      // Wakes the delivery worker of a webhook, by webhook ID
+	client     *http.Client
+	retryDelay time.Duration // Wait after the first failed attempt, doubled after every further one
+	changes    uint64        // Counts the changes to state, to write the newest one only
+	dirty      bool          // Changed since the last write
+
+	fileMu  sync.Mutex // Serializes writing the file, without holding mu
+	written uint64     // changes of the state last written
+}
+
+type webhookState struct {
+	Webhooks   map[string]*Webhook      `json:"webhooks"`   // By ID
+	Cursors    map[string]EventPosition `json:"cursors"`    // Next event to hand out by lowercase wallet address
+	Deliveries map[string]*Delivery     `json:"deliveries"` // Pending and dead letters by ID; delivered ones are dropped
+	Sequence   uint64                   `json:"sequence"`   // Last delivery sequence handed out
+}
+
+var (
+	webhooksOnce sync.Once
+	webhooks     *WebhookRegistry
+)
+
+// Webhooks returns the service wide webhook registry, persisted in the configured data directory
+func Webhooks() *WebhookRegistry {
+	webhooksOnce.Do(func() { webhooks = newWebhookRegistry(config.Get().WebhooksFile()) })
+	return webhooks
+}
+
+// newWebhookRegistry loads the webhook state persisted at path
+func newWebhookRegistry(path string) *WebhookRegistry {
+	wr := &WebhookRegistry{
+		path:       path,
+		state:      webhookState{Webhooks: map[string]*Webhook{}, Cursors: map[string]EventPosition{}, Deliveries: map[string]*Delivery{}},
+		streams:    map[string]context.CancelFunc{},
+		workers:    map[string]chan struct{}{},
+		client:     &http.Client{Timeout: webhookTimeout},
+		retryDelay: webhookRetryDelay,
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &wr.state)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("webhooks: %v", err)
+	}
+	return wr
+}
+
+// Start streams the wallets that have webhooks and resumes the pending deliveries
+func (wr *WebhookRegistry) Start() {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	for _, hook := range wr.state.Webhooks {
+		wr.stream(hook.Wallet)
+		wr.work(hook.ID)
+	}
+	go wr.flush()
+}
+
+// Add registers a webhook for the events of a wallet from the next block on
+func (wr *WebhookRegistry) Add(req WebhookRequest) (Webhook, error) {
+	if !common.IsHexAddress(req.Wallet) {
+		return Webhook{}, fmt.Errorf("%w: wallet %q", ErrInvalidWebhook, req.Wallet)
+	}
+	if u, err := url.Parse(req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Webhook{}, fmt.Errorf("%w: url must be an http or https URL", ErrInvalidWebhook)
+	}
+	events := req.Events
+	if len(events) == 0 {
+		events = []string{"deposit", "submitted", "confirmed", "revoked", "executed"}
+	}
+	for _, name := range events {
+		if !streamedEvents[name] {
+			return Webhook{}, fmt.Errorf("%w: event %q (deposit, submitted, confirmed, revoked or executed)", ErrInvalidWebhook, name)
+		}
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = randomID(32)
+	}
+
+	wallet := common.HexToAddress(req.Wallet)
+	client, _, err := Dial()
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer client.Close()
+	code, err := client.CodeAt(context.Background(), wallet, nil)
+	if err != nil {
+		return Webhook{}, err
+	}
+	if len(code) == 0 {
+		return Webhook{}, fmt.Errorf("%w: %s", bind.ErrNoCode, wallet.Hex())
+	}
+	head, err := client.BlockNumber(context.Background())
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	hook := &Webhook{ID: randomID(8), Wallet: wallet.Hex(), URL: req.URL, Events: events, Secret: secret, CreatedAt: time.Now().UTC()}
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	key := strings.ToLower(hook.Wallet)
+	if _, ok := wr.state.Cursors[key]; !ok {
+		wr.state.Cursors[key] = EventPosition{BlockNumber: head + 1}
+	}
+	wr.state.Webhooks[hook.ID] = hook
+	wr.stream(hook.Wallet)
+	wr.work(hook.ID)
+	return *hook, wr.save()
+}
+
+// Remove deletes a webhook and its undelivered events. The wallet's stream stops with its last webhook.
+func (wr *WebhookRegistry) Remove(id string) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	hook := wr.state.Webhooks[id]
+	if hook == nil {
+		return fmt.Errorf("%w: %s", ErrWebhookNotFound, id)
+	}
+	delete(wr.state.Webhooks, id)
+	for did, d := range wr.state.Deliveries {
+		if d.Webhook == id {
+			delete(wr.state.Deliveries, did)
+		}
+	}
+	wr.wake(id) // The worker sees the webhook is gone and ends
+	delete(wr.workers, id)
+	if len(wr.walletHooks(hook.Wallet)) == 0 {
+		key := strings.ToLower(hook.Wallet)
+		if cancel := wr.streams[key]; cancel != nil {
+			cancel()
+			delete(wr.streams, key)
+		}
+		delete(wr.state.Cursors, key)
+	}
+	return wr.save()
+}
+
+// List returns the webhooks, of wallet only unless it is empty, oldest first and without their secrets
+func (wr *WebhookRegistry) List(wallet string) []Webhook {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	hooks := []Webhook{}
+	for _, hook := range wr.state.Webhooks {
+		if wallet == "" || strings.EqualFold(hook.Wallet, wallet) {
+			h := *hook
+			h.Secret = ""
+			hooks = append(hooks, h)
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].CreatedAt.Before(hooks[j].CreatedAt) })
+	return hooks
+}
+
+// DeadLetters returns the deliveries that were given up, of webhook only unless it is empty, newest first
+func (wr *WebhookRegistry) DeadLetters(webhook string) []Delivery {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	letters := []Delivery{}
+	for _, d := range wr.state.Deliveries {
+		if d.Status == DeliveryFailed && (webhook == "" || d.Webhook == webhook) {
+			letters = append(letters, *d)
+		}
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].UpdatedAt.After(letters[j].UpdatedAt) })
+	return letters
+}
+
+// Replay sends a dead letter again, with a fresh round of attempts. It is queued behind the webhook's
+// pending deliveries, so it arrives out of chain order.
+func (wr *WebhookRegistry) Replay(id string) (Delivery, error) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	d := wr.state.Deliveries[id]
+	if d == nil || d.Status != DeliveryFailed {
+		return Delivery{}, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	wr.state.Sequence++
+	d.Sequence = wr.state.Sequence
+	d.Status, d.Attempts, d.NextAttempt, d.UpdatedAt = DeliveryPending, 0, nil, time.Now().UTC()
+	wr.wake(d.Webhook)
+	return *d, wr.save()
+}
+
+// walletHooks returns the webhooks of wallet. Called with wr.mu held.
+func (wr *WebhookRegistry) walletHooks(wallet string) []*Webhook {
+	var hooks []*Webhook
+	for _, hook := range wr.state.Webhooks {
+		if strings.EqualFold(hook.Wallet, wallet) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// stream starts following the events of wallet unless that runs already. Called with wr.mu held.
+func (wr *WebhookRegistry) stream(wallet string) {
+	key := strings.ToLower(wallet)
+	if wr.streams[key] != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	wr.streams[key] = cancel
+	go func() {
+		for {
+			err := wr.follow(ctx, key)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("webhooks %s: %v, reconnecting", key, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(webhookReconnect):
+			}
+		}
+	}()
+}
+
+// follow streams the events of wallet from its cursor on and queues them for its webhooks until ctx
+// ends or the stream fails
+func (wr *WebhookRegistry) follow(ctx context.Context, wallet string) error {
+	wr.mu.Lock()
+	from := wr.state.Cursors[wallet]
+	wr.mu.Unlock()
+	stream, err := StreamEvents(common.HexToAddress(wallet), &from)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	for {
+		select {
+		case e, open := <-stream.Events():
+			if !open {
+				if err := stream.Err(); err != nil {
+					return err
+				}
+				return errors.New("stream ended")
+			}
+			wr.dispatch(wallet, e)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// dispatch queues event e of wallet for every webhook that wants it and moves the wallet's cursor past it
+func (wr *WebhookRegistry) dispatch(wallet string, e ChainEvent) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	if _, ok := wr.state.Cursors[wallet]; !ok { // Last webhook removed meanwhile
+		return
+	}
+	now := time.Now().UTC()
+	for _, hook := range wr.walletHooks(wallet) {
+		id := deliveryID(hook.ID, e)
+		if !containsString(hook.Events, e.Event) || wr.state.Deliveries[id] != nil {
+			continue
+		}
+		wr.state.Sequence++
+		wr.state.Deliveries[id] = &Delivery{ID: id, Webhook: hook.ID, Sequence: wr.state.Sequence, Event: e, Status: DeliveryPending, CreatedAt: now, UpdatedAt: now}
+		wr.wake(hook.ID)
+	}
+	next := EventPosition{BlockNumber: e.BlockNumber, LogIndex: e.LogIndex + 1}
+	if e.Removed { // The block that replaces it may hold other events at the same position
+		next = e.position()
+	}
+	wr.state.Cursors[wallet] = next
+	wr.changed()
+}
+
+// deliveryID identifies the delivery of e to a webhook. An event handed out again after a restart,
+// before the cursor past it was written, gets the same ID, so receivers can drop the duplicate.
+func deliveryID(webhook string, e ChainEvent) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d/%t", webhook, e.BlockHash, e.LogIndex, e.Removed)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// work starts the delivery worker of webhook id unless it runs. Called with wr.mu held.
+func (wr *WebhookRegistry) work(id string) {
+	if wr.workers[id] != nil {
+		return
+	}
+	wake := make(chan struct{}, 1)
+	wr.workers[id] = wake
+	go wr.deliver(id, wake)
+}
+
+// wake tells the worker of webhook id to look at its queue again. Called with wr.mu held.
+func (wr *WebhookRegistry) wake(id string) {
+	select {
+	case wr.workers[id] <- struct{}{}:
+	default: // Already woken
+	}
+}
+
+// deliver POSTs the pending deliveries of webhook id one at a time, lowest sequence first, until the
+// webhook is removed. A delivery is retried until the receiver accepts it or the attempts run out.
+func (wr *WebhookRegistry) deliver(id string, wake <-chan struct{}) {
+	for {
+		wr.mu.Lock()
+		hook := wr.state.Webhooks[id]
+		if hook == nil {
+			wr.mu.Unlock()
+			return
+		}
+		var d *Delivery
+		for _, p := range wr.state.Deliveries {
+			if p.Webhook == id && p.Status == DeliveryPending && (d == nil || p.queuedBefore(d)) {
+				d = p
+			}
+		}
+		if d == nil {
+			wr.mu.Unlock()
+			<-wake
+			continue
+		}
+		var wait time.Duration
+		if d.NextAttempt != nil {
+			wait = time.Until(*d.NextAttempt)
+		}
+		if wait > 0 {
+			wr.mu.Unlock()
+			select { // Woken early when the webhook is removed
+			case <-time.After(wait):
+			case <-wake:
+			}
+			continue
+		}
+		payload, target, secret := WebhookPayload{Delivery: d.ID, Webhook: hook.ID, Event: d.Event}, hook.URL, hook.Secret
+		wr.mu.Unlock()
+
+		err := wr.post(target, secret, payload)
+
+		wr.mu.Lock()
+		if d = wr.state.Deliveries[payload.Delivery]; d == nil { // Webhook removed meanwhile
+			wr.mu.Unlock()
+			continue
+		}
+		now := time.Now().UTC()
+		d.Attempts++
+		d.UpdatedAt = now
+		switch {
+		case err == nil:
+			delete(wr.state.Deliveries, d.ID)
+		case d.Attempts >= webhookAttempts:
+			log.Printf("webhooks: delivery %s to %s: %v, giving up", d.ID, target, err)
+			d.Status, d.Error, d.NextAttempt = DeliveryFailed, err.Error(), nil
+			wr.prune(id)
+		default:
+			next := now.Add(min(wr.retryDelay<<(d.Attempts-1), webhookMaxDelay))
+			d.Error, d.NextAttempt = err.Error(), &next
+		}
+		wr.changed()
+		wr.mu.Unlock()
+	}
+}
+
+// queuedBefore orders the deliveries of a webhook by sequence, then by chain position
+func (d *Delivery) queuedBefore(o *Delivery) bool {
+	if d.Sequence != o.Sequence {
+		return d.Sequence < o.Sequence
+	}
+	return d.Event.position().before(o.Event.position())
+}
+
+// prune drops the oldest dead letters of webhook id beyond webhookDeadLetters. Called with wr.mu held.
+func (wr *WebhookRegistry) prune(id string) {
+	var letters []*Delivery
+	for _, d := range wr.state.Deliveries {
+		if d.Webhook == id && d.Status == DeliveryFailed {
+			letters = append(letters, d)
+		}
+	}
+	if len(letters) <= webhookDeadLetters {
+		return
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].UpdatedAt.After(letters[j].UpdatedAt) })
+	for _, d := range letters[webhookDeadLetters:] {
+		delete(wr.state.Deliveries, d.ID)
+	}
+}
+
+// post sends payload to target, signed with secret. Any 2xx response counts as delivered.
+func (wr *WebhookRegistry) post(target, secret string, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Multisig-Event", payload.Event.Event)
+	req.Header.Set("X-Multisig-Delivery", payload.Delivery)
+	req.Header.Set("X-Multisig-Timestamp", timestamp)
+	req.Header.Set("X-Multisig-Signature", "sha256="+SignWebhook(secret, timestamp, body))
+	resp, err := wr.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Drain, so the connection is reused
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" under secret, as sent in X-Multisig-Signature
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomID returns n random bytes as hex
+func randomID(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// changed marks the state as changed for flush to write. Called with wr.mu held.
+func (wr *WebhookRegistry) changed() {
+	wr.changes++
+	wr.dirty = true
+}
+
+// flush writes the state every webhookSaveDelay when events or deliveries changed it, so the lock
+// is not held for a write per event
+func (wr *WebhookRegistry) flush() {
+	for range time.Tick(webhookSaveDelay) {
+		wr.mu.Lock()
+		if !wr.dirty {
+			wr.mu.Unlock()
+			continue
+		}
+		wr.dirty = false
+		version := wr.changes
+		data, err := json.MarshalIndent(wr.state, "", "  ")
+		wr.mu.Unlock()
+		if err == nil {
+			err = wr.write(data, version)
+		}
+		if err != nil {
+			log.Printf("webhooks: %v", err)
+		}
+	}
+}
+
+// save writes the webhooks, cursors and deliveries to the webhooks file right away. Called with wr.mu held.
+func (wr *WebhookRegistry) save() error {
+	wr.changes++
+	wr.dirty = false
+	data, err := json.MarshalIndent(wr.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return wr.write(data, wr.changes)
+}
+
+// write replaces the webhooks file with data, the state after version changes, unless a newer state
+// was written meanwhile
+func (wr *WebhookRegistry) write(data []byte, version uint64) error {
+	wr.fileMu.Lock()
+	defer wr.fileMu.Unlock()
+	if version <= wr.written {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(wr.path), 0700); err != nil {
+		return err
+	}
+	tmp := wr.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, wr.path); err != nil { // Atomic replace so a crash never leaves half a file
+		return err
+	}
+	wr.written = version
+	return nil
+}
@@ -0,0 +1,213 @@
+package blockchain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testWallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+// receiver is a webhook endpoint that records every request and answers with the status accept picks
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []received
+	accept   func(r received, attempt int) bool // attempt counts the requests for the same delivery, from 1
+}
+
+type received struct {
+	payload WebhookPayload
+	header  http.Header
+	body    []byte
+	at      time.Time
+}
+
+func newReceiver(t *testing.T, accept func(r received, attempt int) bool) *receiver {
+	rc := &receiver{accept: accept}
+	rc.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := received{header: r.Header.Clone(), body: body, at: time.Now()}
+		if err := json.Unmarshal(body, &req.payload); err != nil {
+			t.Errorf("payload: %v", err)
+		}
+		rc.mu.Lock()
+		rc.requests = append(rc.requests, req)
+		attempt := 0
+		for _, prev := range rc.requests {
+			if prev.payload.Delivery == req.payload.Delivery {
+				attempt++
+			}
+		}
+		ok := rc.accept(req, attempt)
+		rc.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(rc.Close)
+	return rc
+}
+
+// received returns the requests so far
+func (rc *receiver) received() []received {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]received{}, rc.requests...)
+}
+
+// newTestWebhook registers a webhook for the deposits and submissions of testWallet that posts to url,
+// without a chain to stream from; the test dispatches the events itself
+func newTestWebhook(t *testing.T, url string) (*WebhookRegistry, *Webhook) {
+	t.Helper()
+	wr := newWebhookRegistry(filepath.Join(t.TempDir(), "webhooks.json"))
+	wr.retryDelay = 5 * time.Millisecond
+	hook := &Webhook{ID: "hook", Wallet: testWallet, URL: url, Events: []string{"deposit", "submitted"}, Secret: "s3cret", CreatedAt: time.Now()}
+	wr.mu.Lock()
+	wr.state.Webhooks[hook.ID] = hook
+	wr.state.Cursors[strings.ToLower(testWallet)] = EventPosition{}
+	wr.work(hook.ID)
+	wr.mu.Unlock()
+	t.Cleanup(func() { wr.Remove(hook.ID) })
+	return wr, hook
+}
+
+func testEvent(name string, block uint64) ChainEvent {
+	return ChainEvent{Wallet: testWallet, Event: name, BlockNumber: block, BlockHash: common.BigToHash(common.Big1).Hex(), LogIndex: uint(block)}
+}
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); !cond(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+// The receiver can check every request with the shared secret
+func TestWebhookSignature(t *testing.T) {
+	rc := newReceiver(t, func(received, int) bool { return true })
+	wr, hook := newTestWebhook(t, rc.URL)
+	wr.dispatch(strings.ToLower(testWallet), testEvent("deposit", 1))
+	waitFor(t, "the delivery", func() bool { return len(rc.received()) == 1 })
+
+	r := rc.received()[0]
+	timestamp := r.header.Get("X-Multisig-Timestamp")
+	mac := hmac.New(sha256.New, []byte(hook.Secret))
+	mac.Write([]byte(timestamp + "." + string(r.body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := r.header.Get("X-Multisig-Signature"); got != want {
+		t.Fatalf("got signature %s, want %s", got, want)
+	}
+	if got := "sha256=" + SignWebhook(hook.Secret, timestamp, r.body); got != want {
+		t.Fatalf("SignWebhook gave %s, want %s", got, want)
+	}
+	if "sha256="+SignWebhook("other", timestamp, r.body) == want || "sha256="+SignWebhook(hook.Secret, timestamp+"1", r.body) == want {
+		t.Fatal("signature does not depend on the secret and the timestamp")
+	}
+	if r.header.Get("X-Multisig-Event") != "deposit" || r.header.Get("X-Multisig-Delivery") != r.payload.Delivery || r.payload.Webhook != hook.ID {
+		t.Fatalf("headers %v for payload %+v", r.header, r.payload)
+	}
+}
+
+// A failing delivery is retried with growing delays and holds back the events after it
+func TestWebhookRetriesInOrder(t *testing.T) {
+	first := ""
+	rc := newReceiver(t, func(r received, attempt int) bool {
+		if first == "" {
+			first = r.payload.Delivery
+		}
+		return r.payload.Delivery != first || attempt > 3 // The first event fails three times
+	})
+	wr, _ := newTestWebhook(t, rc.URL)
+	wallet := strings.ToLower(testWallet)
+	wr.dispatch(wallet, testEvent("deposit", 1))
+	wr.dispatch(wallet, testEvent("confirmed", 2)) // Not subscribed
+	wr.dispatch(wallet, testEvent("submitted", 3))
+	wr.dispatch(wallet, testEvent("deposit", 4))
+	waitFor(t, "all deliveries", func() bool { return len(rc.received()) == 6 })
+
+	var blocks []uint64
+	for _, r := range rc.received() {
+		blocks = append(blocks, r.payload.Event.BlockNumber)
+	}
+	if want := []uint64{1, 1, 1, 1, 3, 4}; !slices.Equal(blocks, want) {
+		t.Fatalf("received blocks %v, want %v", blocks, want)
+	}
+	requests := rc.received()
+	for i := 1; i < 4; i++ {
+		gap := requests[i].at.Sub(requests[i-1].at)
+		if want := wr.retryDelay << (i - 1); gap < want {
+			t.Fatalf("retry %d after %s, want at least %s", i, gap, want)
+		}
+	}
+	waitFor(t, "the queue to empty", func() bool {
+		wr.mu.Lock()
+		defer wr.mu.Unlock()
+		return len(wr.state.Deliveries) == 0
+	})
+}
+
+// After webhookAttempts failures a delivery becomes a dead letter, the next event goes out, and a
+// replay sends the dead letter again
+func TestWebhookDeadLetterReplay(t *testing.T) {
+	var mu sync.Mutex
+	up := false
+	rc := newReceiver(t, func(r received, attempt int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return up || r.payload.Event.BlockNumber != 1
+	})
+	wr, hook := newTestWebhook(t, rc.URL)
+	wallet := strings.ToLower(testWallet)
+	wr.dispatch(wallet, testEvent("deposit", 1))
+	wr.dispatch(wallet, testEvent("deposit", 2))
+	waitFor(t, "the second event", func() bool { return len(rc.received()) == webhookAttempts+1 })
+
+	for i, r := range rc.received() {
+		want := uint64(1)
+		if i == webhookAttempts {
+			want = 2
+		}
+		if r.payload.Event.BlockNumber != want {
+			t.Fatalf("request %d was block %d, want %d", i, r.payload.Event.BlockNumber, want)
+		}
+	}
+	letters := wr.DeadLetters(hook.ID)
+	if len(letters) != 1 || letters[0].Status != DeliveryFailed || letters[0].Attempts != webhookAttempts || letters[0].Event.BlockNumber != 1 || letters[0].Error == "" {
+		t.Fatalf("dead letters %+v, want block 1 after %d attempts", letters, webhookAttempts)
+	}
+
+	mu.Lock()
+	up = true
+	mu.Unlock()
+	replayed, err := wr.Replay(letters[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Status != DeliveryPending || replayed.Attempts != 0 {
+		t.Fatalf("replayed %+v, want a fresh pending delivery", replayed)
+	}
+	waitFor(t, "the replay", func() bool { return len(rc.received()) == webhookAttempts+2 })
+	if r := rc.received()[webhookAttempts+1]; r.payload.Delivery != letters[0].ID {
+		t.Fatalf("replay delivered %s, want %s", r.payload.Delivery, letters[0].ID)
+	}
+	waitFor(t, "the dead letter to go", func() bool { return len(wr.DeadLetters(hook.ID)) == 0 })
+	if _, err := wr.Replay(letters[0].ID); err == nil {
+		t.Fatal("replayed a delivered event again")
+	}
+}
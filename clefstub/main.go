@@ -0,0 +1,192 @@
+// clefstub is a minimal stand-in for Clef, go-ethereum's external signer, so the clef signer type
+// can be exercised on one machine. It serves account_version, account_list, account_signTransaction
+// and account_signData over HTTP JSON-RPC, holding keys unlocked from a keystore directory or given
+// as hex in CLEFSTUB_KEYS. Requests are approved by simple rules instead of a human.
+//
+// Not a security boundary: use the real Clef for anything beyond a developer machine.
+package main
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// rules are the approval checks applied to every signing request
+type rules struct {
+	chainID  *big.Int                // Only sign for this chain
+	maxValue *big.Int                // Reject transactions moving more wei (nil = no limit)
+	allowTo  map[common.Address]bool // Only these recipients (empty = any); contract creation is always allowed
+}
+
+// accountAPI implements the "account" namespace of the Clef external API
+type accountAPI struct {
+	keys  map[common.Address]*ecdsa.PrivateKey
+	rules rules
+}
+
+// signTxResult mirrors Clef's account_signTransaction response
+type signTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (api *accountAPI) Version() string {
+	return "6.0.0-clefstub"
+}
+
+func (api *accountAPI) List() []common.Address {
+	var list []common.Address
+	for address := range api.keys {
+		list = append(list, address)
+	}
+	return list
+}
+
+func (api *accountAPI) SignTransaction(args apitypes.SendTxArgs, methodSelector *string) (*signTxResult, error) {
+	key, ok := api.keys[args.From.Address()]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", args.From.Address().Hex())
+	}
+	if args.ChainID == nil || args.ChainID.ToInt().Cmp(api.rules.chainID) != 0 {
+		return nil, errors.New("request denied: wrong chain id")
+	}
+	if api.rules.maxValue != nil && args.Value.ToInt().Cmp(api.rules.maxValue) > 0 {
+		return nil, errors.New("request denied: value above limit")
+	}
+	if args.To != nil && len(api.rules.allowTo) > 0 && !api.rules.allowTo[args.To.Address()] {
+		return nil, errors.New("request denied: recipient not allowed")
+	}
+
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := types.SignTx(tx, types.NewLondonSigner(api.rules.chainID), key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	to := "contract creation"
+	if args.To != nil {
+		to = args.To.Address().Hex()
+	}
+	log.Printf("approved tx from %s to %s value %s nonce %d", args.From.Address().Hex(), to, args.Value.ToInt(), uint64(args.Nonce))
+	return &signTxResult{Raw: raw, Tx: signed}, nil
+}
+
+func (api *accountAPI) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	key, ok := api.keys[addr.Address()]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", addr.Address().Hex())
+	}
+
+	var hash []byte
+	switch contentType {
+	case accounts.MimetypeTextPlain: // EIP-191 personal message
+		hash = accounts.TextHash(data)
+	case accounts.MimetypeDataWithValidator: // EIP-191 version 0x00: 20 byte validator followed by the message
+		if len(data) < common.AddressLength {
+			return nil, errors.New("data/validator needs a validator address")
+		}
+		hash = crypto.Keccak256(append([]byte{0x19, 0x00}, data...))
+	default:
+		return nil, fmt.Errorf("content type %q not supported by clefstub", contentType)
+	}
+
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // Clef returns Ethereum style recovery ids
+	log.Printf("approved %s signature by %s", contentType, addr.Address().Hex())
+	return sig, nil
+}
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8550", "HTTP listen address")
+	keystoreDir := flag.String("keystore", "", "keystore directory whose accounts are unlocked with CLEFSTUB_PASSPHRASE")
+	chainID := flag.Int64("chainid", 1337, "chain ID requests must target")
+	maxValue := flag.String("max-value", "", "reject transactions moving more wei than this")
+	allowTo := flag.String("allow-to", "", "comma separated recipient allowlist")
+	flag.Parse()
+
+	api := &accountAPI{keys: map[common.Address]*ecdsa.PrivateKey{}, rules: rules{chainID: big.NewInt(*chainID), allowTo: map[common.Address]bool{}}}
+	if *maxValue != "" {
+		v, ok := new(big.Int).SetString(*maxValue, 10)
+		if !ok {
+			log.Fatal("invalid -max-value")
+		}
+		api.rules.maxValue = v
+	}
+	for _, a := range strings.Split(*allowTo, ",") {
+		if common.IsHexAddress(strings.TrimSpace(a)) {
+			api.rules.allowTo[common.HexToAddress(strings.TrimSpace(a))] = true
+		}
+	}
+
+	for _, hexKey := range strings.Split(os.Getenv("CLEFSTUB_KEYS"), ",") {
+		if hexKey = strings.TrimSpace(hexKey); hexKey == "" {
+			continue
+		}
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+		if err != nil {
+			log.Fatal("invalid key in CLEFSTUB_KEYS: ", err)
+		}
+		api.keys[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+	if *keystoreDir != "" {
+		if err := loadKeystore(api.keys, *keystoreDir, os.Getenv("CLEFSTUB_PASSPHRASE")); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(api.keys) == 0 {
+		log.Fatal("no keys: set CLEFSTUB_KEYS or -keystore with CLEFSTUB_PASSPHRASE")
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", api); err != nil {
+		log.Fatal(err)
+	}
+	for _, address := range api.List() {
+		log.Printf("serving account %s", address.Hex())
+	}
+	log.Printf("clefstub listening on http://%s (chain %d)", *listen, *chainID)
+	log.Fatal(http.ListenAndServe(*listen, server))
+}
+
+// loadKeystore decrypts every key file in dir with passphrase
+func loadKeystore(keys map[common.Address]*ecdsa.PrivateKey, dir, passphrase string) error {
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	for _, account := range ks.Accounts() {
+		keyJSON, err := os.ReadFile(account.URL.Path)
+		if err != nil {
+			return err
+		}
+		key, err := keystore.DecryptKey(keyJSON, passphrase)
+		if err != nil {
+			return fmt.Errorf("%s: %w", account.Address.Hex(), err)
+		}
+		keys[key.Address] = key.PrivateKey
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"encoding/json" // Config files are plain JSON
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPath is the config file read when MULTISIG_CONFIG is not set
+const DefaultPath = "config.json"
+
+// Network describes one Ethereum network the service can talk to
+type Network struct {
+	RPCURLs       []string `json:"rpcUrls"`       // Tried in order until one answers
+	ChainID       int64    `json:"chainId"`       // Expected chain ID, the connection is refused on mismatch
+	ExplorerURL   string   `json:"explorerUrl"`   // Block explorer base, e.g. https://sepolia.etherscan.io
+	Confirmations uint64   `json:"confirmations"` // Default number of blocks to wait before treating a tx as final
+	WSURL         string   `json:"wsUrl"`         // Websocket endpoint for event subscriptions; without it events are polled
+}
+
+// TxURL returns the explorer link for a transaction hash, or "" when no explorer is configured
+func (n Network) TxURL(hash string) string {
+	if n.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimRight(n.ExplorerURL, "/") + "/tx/" + hash
+}
+
+// AddressURL returns the explorer link for an address, or "" when no explorer is configured
+func (n Network) AddressURL(address string) string {
+	if n.ExplorerURL == "" {
+		return ""
+	}
+	return strings.TrimRight(n.ExplorerURL, "/") + "/address/" + address
+}
+
+// SignerConfig declares a named signer. Key material never appears in the file itself.
+type SignerConfig struct {
+	Type    string `json:"type"`              // "key", "keystore", "remote" or "clef"
+	Address string `json:"address,omitempty"` // Account of keystore, remote and clef signers
+	KeyEnv  string `json:"keyEnv,omitempty"`  // key: environment variable holding the hex private key
+	URL     string `json:"url,omitempty"`     // remote: base URL of the signing service; clef: RPC endpoint or IPC path
+}
+
+// Config is the service configuration shared by the API, the blockchain layer and the tools
+type Config struct {
+	Network    string             `json:"network"`    // Name of the active network
+	Networks   map[string]Network `json:"networks"`   // All known networks by name
+	ListenAddr string             `json:"listenAddr"` // HTTP listen address of the API server
+	PrivateKey string             `json:"privateKey"` // Legacy hex key, registered as the "default" key signer
+	DataDir    string             `json:"dataDir"`    // Directory for server side state such as the keystore
+	GasMargin  uint64             `json:"gasMargin"`  // Percent added to gas estimates as a safety margin
+
+	Signers       map[string]SignerConfig `json:"signers"`       // Named signers requests can refer to
+	DefaultSigner string                  `json:"defaultSigner"` // Signer used when a request names none
+	Executor      string                  `json:"executor"`      // Signer the auto-executor executes ready proposals with; empty disables it
+}
+
+// KeystoreDir is where the encrypted (Web3 Secret Storage v3) account files live
+func (c *Config) KeystoreDir() string {
+	return filepath.Join(c.DataDir, "keystore")
+}
+
+// HDDir is where HD wallets (encrypted mnemonic + derived account list) are kept
+func (c *Config) HDDir() string {
+	return filepath.Join(c.DataDir, "hd")
+}
+
+// NonceFile is where the nonce manager persists the nonces handed out per account
+func (c *Config) NonceFile() string {
+	return filepath.Join(c.DataDir, "nonces.json")
+}
+
+// DeploymentsFile is where multisig deployment jobs are kept until and after they are verified
+func (c *Config) DeploymentsFile() string {
+	return filepath.Join(c.DataDir, "deployments.json")
+}
+
+// TransactionsFile is where the transaction tracker keeps every transaction the service sent
+func (c *Config) TransactionsFile() string {
+	return filepath.Join(c.DataDir, "transactions.json")
+}
+
+// ABIsFile is where user uploaded ABIs for decoding proposal calldata are kept
+func (c *Config) ABIsFile() string {
+	return filepath.Join(c.DataDir, "abis.json")
+}
+
+// IndexFile is where the event indexer keeps the wallets it follows and their decoded events on the active chain
+func (c *Config) IndexFile() string {
+	return c.chainFile("events")
+}
+
+// ExecutorFile is where the auto-executor keeps its per-wallet switches and the proposals it executed on the active chain
+func (c *Config) ExecutorFile() string {
+	return c.chainFile("executor")
+}
+
+// WebhooksFile is where the webhooks of wallets on the active chain, their secrets and undelivered events are kept
+func (c *Config) WebhooksFile() string {
+	return c.chainFile("webhooks")
+}
+
+// chainFile is the JSON file name in DataDir for state that belongs to the active network's chain, by
+// chain ID or by network name when it has none, so two networks sharing DataDir never mix their state
+func (c *Config) chainFile(name string) string {
+	key := c.Network
+	if n, err := c.Active(); err == nil && n.ChainID != 0 {
+		key = strconv.FormatInt(n.ChainID, 10)
+	}
+	return filepath.Join(c.DataDir, name+"-"+key+".json")
+}
+
+// Default returns the built-in configuration: a local devnet plus Sepolia and mainnet without RPC endpoints
+func Default() *Config {
+	return &Config{
+		Network:    "devnet",
+		ListenAddr: ":8080",
+		DataDir:    "data",
+		GasMargin:  20,
+		Networks: map[string]Network{
+			"devnet": {
+				RPCURLs:       []string{"http://127.0.0.1:8545"},
+				ChainID:       1337,
+				Confirmations: 1,
+			},
+			"sepolia": {
+				ChainID:       11155111,
+				ExplorerURL:   "https://sepolia.etherscan.io",
+				Confirmations: 2,
+			},
+			"mainnet": {
+				ChainID:       1,
+				ExplorerURL:   "https://etherscan.io",
+				Confirmations: 12,
+			},
+		},
+	}
+}
+
+// Load reads the config file at path on top of the defaults and then applies environment overrides.
+// A missing file is not an error, so the service can be configured from the environment alone.
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cfg.Active(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Read is Load without checking the active network, for tools that also work offline. A config file
+// that cannot be read or parsed is still an error.
+func Read(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		var file Config
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		cfg.merge(&file)
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// merge overlays the values set in file onto c. Networks are merged field by field so a file
+// can, for example, only add an RPC URL to the built-in sepolia entry.
+func (c *Config) merge(file *Config) {
+	if file.Network != "" {
+		c.Network = file.Network
+	}
+	if file.ListenAddr != "" {
+		c.ListenAddr = file.ListenAddr
+	}
+	if file.PrivateKey != "" {
+		c.PrivateKey = file.PrivateKey
+	}
+	if file.DataDir != "" {
+		c.DataDir = file.DataDir
+	}
+	if file.DefaultSigner != "" {
+		c.DefaultSigner = file.DefaultSigner
+	}
+	if file.GasMargin != 0 {
+		c.GasMargin = file.GasMargin
+	}
+	if file.Executor != "" {
+		c.Executor = file.Executor
+	}
+	if len(file.Signers) > 0 {
+		c.Signers = file.Signers
+	}
+	for name, n := range file.Networks {
+		base := c.Networks[name]
+		if len(n.RPCURLs) > 0 {
+			base.RPCURLs = n.RPCURLs
+		}
+		if n.ChainID != 0 {
+			base.ChainID = n.ChainID
+		}
+		if n.ExplorerURL != "" {
+			base.ExplorerURL = n.ExplorerURL
+		}
+		if n.Confirmations != 0 {
+			base.Confirmations = n.Confirmations
+		}
+		if n.WSURL != "" {
+			base.WSURL = n.WSURL
+		}
+		c.Networks[name] = base
+	}
+}
+
+// applyEnv applies the MULTISIG_* environment overrides. Network specific values only touch the active network.
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("MULTISIG_NETWORK"); v != "" {
+		c.Network = v
+	}
+	if v := os.Getenv("MULTISIG_LISTEN_ADDR"); v != "" {
+		c.ListenAddr = v
+	}
+	if v := os.Getenv("MULTISIG_PRIVATE_KEY"); v != "" {
+		c.PrivateKey = v
+	}
+	if v := os.Getenv("MULTISIG_DATA_DIR"); v != "" {
+		c.DataDir = v
+	}
+	if v := os.Getenv("MULTISIG_DEFAULT_SIGNER"); v != "" {
+		c.DefaultSigner = v
+	}
+	if v := os.Getenv("MULTISIG_EXECUTOR"); v != "" {
+		c.Executor = v
+	}
+	if v := os.Getenv("MULTISIG_GAS_MARGIN"); v != "" {
+		margin, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MULTISIG_GAS_MARGIN %q: %w", v, err)
+		}
+		c.GasMargin = margin
+	}
+
+	n := c.Networks[c.Network]
+	if v := os.Getenv("MULTISIG_RPC_URL"); v != "" {
+		n.RPCURLs = strings.Split(v, ",") // Comma separated list of endpoints
+	}
+	if v := os.Getenv("MULTISIG_CHAIN_ID"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MULTISIG_CHAIN_ID %q: %w", v, err)
+		}
+		n.ChainID = id
+	}
+	if v := os.Getenv("MULTISIG_EXPLORER_URL"); v != "" {
+		n.ExplorerURL = v
+	}
+	if v := os.Getenv("MULTISIG_WS_URL"); v != "" {
+		n.WSURL = v
+	}
+	if v := os.Getenv("MULTISIG_CONFIRMATIONS"); v != "" {
+		confs, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MULTISIG_CONFIRMATIONS %q: %w", v, err)
+		}
+		n.Confirmations = confs
+	}
+	if c.Networks == nil {
+		c.Networks = map[string]Network{}
+	}
+	if _, known := c.Networks[c.Network]; known || len(n.RPCURLs) > 0 {
+		c.Networks[c.Network] = n
+	}
+	return nil
+}
+
+// Active returns the network selected by c.Network
+func (c *Config) Active() (Network, error) {
+	n, ok := c.Networks[c.Network]
+	if !ok {
+		return Network{}, fmt.Errorf("unknown network %q", c.Network)
+	}
+	if len(n.RPCURLs) == 0 {
+		return Network{}, fmt.Errorf("no RPC URL configured for network %q (set it in the config file or MULTISIG_RPC_URL)", c.Network)
+	}
+	return n, nil
+}
+
+var (
+	mu      sync.RWMutex
+	current *Config
+)
+
+// Set installs cfg as the process wide configuration
+func Set(cfg *Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = cfg
+}
+
+// Get returns the process wide configuration installed with Set. Programs Load (or Read) the config
+// file and Set the result at startup, so a broken file stops them there; Get never reads the file
+// itself. Before Set it returns the built-in defaults.
+func Get() *Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		return Default()
+	}
+	return current
+}
+
+// Path returns the config file location from MULTISIG_CONFIG, falling back to DefaultPath
+func Path() string {
+	if p := os.Getenv("MULTISIG_CONFIG"); p != "" {
+		return p
+	}
+	return DefaultPath
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var envVars = []string{
+	"MULTISIG_NETWORK", "MULTISIG_LISTEN_ADDR", "MULTISIG_PRIVATE_KEY", "MULTISIG_DATA_DIR", "MULTISIG_DEFAULT_SIGNER",
+	"MULTISIG_EXECUTOR", "MULTISIG_GAS_MARGIN", "MULTISIG_RPC_URL", "MULTISIG_CHAIN_ID", "MULTISIG_EXPLORER_URL",
+	"MULTISIG_WS_URL", "MULTISIG_CONFIRMATIONS",
+}
+
+// writeConfig clears the MULTISIG_* environment and writes content as a config file
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	for _, name := range envVars {
+		t.Setenv(name, "") // Empty counts as unset
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadMergesFileOverDefaults(t *testing.T) {
+	path := writeConfig(t, `{
+		"network": "sepolia",
+		"gasMargin": 35,
+		"networks": {
+			"sepolia": {"rpcUrls": ["https://rpc.example/a", "https://rpc.example/b"], "wsUrl": "wss://rpc.example/ws"},
+			"custom": {"rpcUrls": ["http://10.0.0.1:8545"], "chainId": 4242}
+		},
+		"signers": {"ops": {"type": "keystore", "address": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}}
+	}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Network != "sepolia" || cfg.GasMargin != 35 || cfg.ListenAddr != ":8080" || cfg.DataDir != "data" {
+		t.Fatalf("got %+v, want the file's network and margin over the default listen address and data dir", cfg)
+	}
+	sepolia, err := cfg.Active()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Network{
+		RPCURLs:       []string{"https://rpc.example/a", "https://rpc.example/b"},
+		ChainID:       11155111, // Kept from the built-in entry
+		ExplorerURL:   "https://sepolia.etherscan.io",
+		Confirmations: 2,
+		WSURL:         "wss://rpc.example/ws",
+	}
+	if !reflect.DeepEqual(sepolia, want) {
+		t.Fatalf("sepolia is %+v, want %+v", sepolia, want)
+	}
+	if cfg.Networks["custom"].ChainID != 4242 || cfg.Networks["mainnet"].ChainID != 1 {
+		t.Fatalf("networks %+v, want the file's custom network next to the built-in ones", cfg.Networks)
+	}
+	if cfg.Signers["ops"].Type != "keystore" {
+		t.Fatalf("signers %+v", cfg.Signers)
+	}
+}
+
+func TestReadEnvOverrides(t *testing.T) {
+	path := writeConfig(t, `{"network": "sepolia", "listenAddr": ":9000", "networks": {"sepolia": {"rpcUrls": ["https://file.example"]}}}`)
+	t.Setenv("MULTISIG_NETWORK", "devnet")
+	t.Setenv("MULTISIG_LISTEN_ADDR", ":9100")
+	t.Setenv("MULTISIG_DATA_DIR", "/var/lib/multisig")
+	t.Setenv("MULTISIG_GAS_MARGIN", "5")
+	t.Setenv("MULTISIG_EXECUTOR", "ops")
+	t.Setenv("MULTISIG_RPC_URL", "http://a:8545,http://b:8545")
+	t.Setenv("MULTISIG_CHAIN_ID", "31337")
+	t.Setenv("MULTISIG_CONFIRMATIONS", "3")
+	t.Setenv("MULTISIG_WS_URL", "ws://a:8546")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Network != "devnet" || cfg.ListenAddr != ":9100" || cfg.DataDir != "/var/lib/multisig" || cfg.GasMargin != 5 || cfg.Executor != "ops" {
+		t.Fatalf("got %+v, want the environment over the file", cfg)
+	}
+	devnet := cfg.Networks["devnet"]
+	if !reflect.DeepEqual(devnet.RPCURLs, []string{"http://a:8545", "http://b:8545"}) || devnet.ChainID != 31337 || devnet.Confirmations != 3 || devnet.WSURL != "ws://a:8546" {
+		t.Fatalf("devnet is %+v, want the environment's endpoints and chain", devnet)
+	}
+	if got := cfg.Networks["sepolia"].RPCURLs; !reflect.DeepEqual(got, []string{"https://file.example"}) {
+		t.Fatalf("sepolia endpoints %v, network overrides must only touch the active network", got)
+	}
+
+	// A network only the environment knows
+	t.Setenv("MULTISIG_NETWORK", "private")
+	t.Setenv("MULTISIG_CHAIN_ID", "")
+	if cfg, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := cfg.Active(); !reflect.DeepEqual(n.RPCURLs, []string{"http://a:8545", "http://b:8545"}) {
+		t.Fatalf("private network is %+v", n)
+	}
+}
+
+func TestReadWithoutFile(t *testing.T) {
+	writeConfig(t, "")
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	cfg, err := Read(missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Fatalf("got %+v, want the defaults", cfg)
+	}
+	t.Setenv("MULTISIG_NETWORK", "sepolia")
+	if _, err := Load(missing); err == nil || !strings.Contains(err.Error(), "no RPC URL") {
+		t.Fatalf("got %v, want the missing RPC URL reported", err)
+	}
+	t.Setenv("MULTISIG_RPC_URL", "https://rpc.example")
+	if _, err := Load(missing); err != nil {
+		t.Fatalf("configured from the environment alone: %v", err)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		file string
+		env  map[string]string
+		want string
+	}{
+		"broken json":      {file: `{"network": "sepolia",`, want: "invalid config file"},
+		"wrong type":       {file: `{"gasMargin": "twenty"}`, want: "invalid config file"},
+		"gas margin":       {file: `{}`, env: map[string]string{"MULTISIG_GAS_MARGIN": "-1"}, want: "invalid MULTISIG_GAS_MARGIN"},
+		"chain id":         {file: `{}`, env: map[string]string{"MULTISIG_CHAIN_ID": "sepolia"}, want: "invalid MULTISIG_CHAIN_ID"},
+		"confirmations":    {file: `{}`, env: map[string]string{"MULTISIG_CONFIRMATIONS": "many"}, want: "invalid MULTISIG_CONFIRMATIONS"},
+		"unknown network":  {file: `{"network": "goerli"}`, want: `unknown network "goerli"`},
+		"network from env": {file: `{}`, env: map[string]string{"MULTISIG_NETWORK": "holesky"}, want: `unknown network "holesky"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, tc.file)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if _, err := Load(path); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+
+	// A file that exists but cannot be read is an error too, not the defaults
+	writeConfig(t, "")
+	if _, err := Read(t.TempDir()); err == nil || !strings.Contains(err.Error(), "failed to read config file") {
+		t.Fatalf("reading a directory: got %v", err)
+	}
+}
+
+func TestChainFiles(t *testing.T) {
+	cfg := Default()
+	cfg.DataDir = "state"
+	if got := cfg.IndexFile(); got != filepath.Join("state", "events-1337.json") {
+		t.Fatalf("devnet index file %s", got)
+	}
+	cfg.Network = "sepolia" // No RPC URL, so not active: fall back to the network name
+	if got := cfg.WebhooksFile(); got != filepath.Join("state", "webhooks-sepolia.json") {
+		t.Fatalf("sepolia webhooks file %s", got)
+	}
+}
@@ -6,6 +6,11 @@ contract MultisigWallet {
     event SubmitTransaction(uint indexed txIndex, address indexed to, uint value, bytes data);
     event ConfirmTransaction(address indexed owner, uint indexed txIndex);
     event ExecuteTransaction(uint indexed txIndex);
+    event RevokeConfirmation(address indexed owner, uint indexed txIndex);
+    event OwnerAddition(address indexed owner);
+    event OwnerRemoval(address indexed owner);
+    event RequirementChange(uint requiredConfirmations);
+    event TimelockChange(uint delay, uint minValue);
 
     address[] public owners;
     mapping(address => bool) public isOwner;
@@ -16,29 +21,48 @@ contract MultisigWallet {
         uint value;
         bytes data;
         bool executed;
-        uint confirmations;
     }
 
-    Transaction[] public transactions;
-    mapping(uint => mapping(address => bool)) public isConfirmed;
+    // An owner's confirmation counts while the owner is still in the term it was given in
+    struct Confirmation {
+        uint term;
+        uint time;
+    }
+
+    Transaction[] private _transactions;
+    mapping(uint => mapping(address => Confirmation)) private _confirmations;
+    // Bumped whenever an address becomes an owner, so a removed owner's confirmations stay void
+    // without touching every pending proposal
+    mapping(address => uint) private _ownerTerm;
+
+    // Cooling-off period between a proposal reaching the threshold and its execution
+    uint public timelockDelay;
+    uint public timelockMinValue; // Plain transfers of less wei skip the delay; 0 for none
+    uint private _requirementChangedAt; // A threshold change restarts the delay of every proposal
 
     modifier onlyOwner() {
         require(isOwner[msg.sender], "not owner");
         _;
     }
 
+    // Owner and threshold changes are proposals the wallet executes on itself
+    modifier onlyWallet() {
+        require(msg.sender == address(this), "only wallet");
+        _;
+    }
+
     modifier txExists(uint _txIndex) {
-        require(_txIndex < transactions.length, "tx does not exist");
+        require(_txIndex < _transactions.length, "tx does not exist");
         _;
     }
 
     modifier notConfirmed(uint _txIndex) {
-        require(!isConfirmed[_txIndex][msg.sender], "already confirmed");
+        require(!isConfirmed(_txIndex, msg.sender), "already confirmed");
         _;
     }
 
     modifier notExecuted(uint _txIndex) {
-        require(!transactions[_txIndex].executed, "already executed");
+        require(!_transactions[_txIndex].executed, "already executed");
         _;
     }
 
@@ -50,6 +74,7 @@ contract MultisigWallet {
             address owner = _owners[i];
             require(owner != address(0) && !isOwner[owner], "invalid owner");
             isOwner[owner] = true;
+            _ownerTerm[owner] += 1;
             owners.push(owner);
         }
 
@@ -61,15 +86,14 @@ contract MultisigWallet {
     }
 
     function submitTransaction(address _to, uint _value, bytes memory _data) public onlyOwner {
-        transactions.push(Transaction({
+        _transactions.push(Transaction({
             to: _to,
             value: _value,
             data: _data,
-            executed: false,
-            confirmations: 0
+            executed: false
         }));
 
-        emit SubmitTransaction(transactions.length - 1, _to, _value, _data);
+        emit SubmitTransaction(_transactions.length - 1, _to, _value, _data);
     }
 
     function confirmTransaction(uint _txIndex)
@@ -79,21 +103,34 @@ contract MultisigWallet {
         notConfirmed(_txIndex)
         notExecuted(_txIndex)
     {
-        isConfirmed[_txIndex][msg.sender] = true;
-        transactions[_txIndex].confirmations += 1;
+        _confirmations[_txIndex][msg.sender] = Confirmation(_ownerTerm[msg.sender], block.timestamp);
 
         emit ConfirmTransaction(msg.sender, _txIndex);
     }
 
+    function revokeConfirmation(uint _txIndex)
+        public
+        onlyOwner
+        txExists(_txIndex)
+        notExecuted(_txIndex)
+    {
+        require(isConfirmed(_txIndex, msg.sender), "tx not confirmed");
+
+        delete _confirmations[_txIndex][msg.sender];
+
+        emit RevokeConfirmation(msg.sender, _txIndex);
+    }
+
     function executeTransaction(uint _txIndex)
         public
         onlyOwner
         txExists(_txIndex)
         notExecuted(_txIndex)
     {
-        Transaction storage txn = transactions[_txIndex];
+        Transaction storage txn = _transactions[_txIndex];
 
-        require(txn.confirmations >= requiredConfirmations, "not enough confirmations");
+        require(_confirmationCount(_txIndex) >= requiredConfirmations, "not enough confirmations");
+        require(block.timestamp >= executableAt(_txIndex), "timelocked");
 
         txn.executed = true;
 
@@ -103,8 +140,119 @@ contract MultisigWallet {
         emit ExecuteTransaction(_txIndex);
     }
 
+    function addOwner(address _owner) public onlyWallet {
+        require(_owner != address(0) && !isOwner[_owner], "invalid owner");
+
+        isOwner[_owner] = true;
+        _ownerTerm[_owner] += 1;
+        owners.push(_owner);
+
+        emit OwnerAddition(_owner);
+    }
+
+    function removeOwner(address _owner) public onlyWallet {
+        require(isOwner[_owner], "not owner");
+        require(owners.length - 1 >= requiredConfirmations, "too few owners left");
+
+        isOwner[_owner] = false;
+        for (uint i; i < owners.length; i++) {
+            if (owners[i] == _owner) {
+                owners[i] = owners[owners.length - 1];
+                owners.pop();
+                break;
+            }
+        }
+
+        emit OwnerRemoval(_owner);
+    }
+
+    function replaceOwner(address _owner, address _newOwner) public onlyWallet {
+        require(isOwner[_owner], "not owner");
+        require(_newOwner != address(0) && !isOwner[_newOwner], "invalid owner");
+
+        for (uint i; i < owners.length; i++) {
+            if (owners[i] == _owner) {
+                owners[i] = _newOwner;
+                break;
+            }
+        }
+        isOwner[_owner] = false;
+        isOwner[_newOwner] = true;
+        _ownerTerm[_newOwner] += 1;
+
+        emit OwnerRemoval(_owner);
+        emit OwnerAddition(_newOwner);
+    }
+
+    function changeRequirement(uint _requiredConfirmations) public onlyWallet {
+        require(_requiredConfirmations > 0 && _requiredConfirmations <= owners.length, "invalid confirmations");
+
+        requiredConfirmations = _requiredConfirmations;
+        _requirementChangedAt = block.timestamp;
+
+        emit RequirementChange(_requiredConfirmations);
+    }
+
+    function changeTimelock(uint _delay, uint _minValue) public onlyWallet {
+        timelockDelay = _delay;
+        timelockMinValue = _minValue;
+
+        emit TimelockChange(_delay, _minValue);
+    }
+
+    // Whether _owner currently confirms the proposal. Confirmations of removed owners, or given
+    // before an owner was removed and added again, do not count.
+    function isConfirmed(uint _txIndex, address _owner) public view returns (bool) {
+        return isOwner[_owner] && _confirmations[_txIndex][_owner].term == _ownerTerm[_owner];
+    }
+
+    // When a proposal reached the threshold, 0 while below it: the time of the confirmation that
+    // made up the required number, or the last threshold change if that came later. Computed on
+    // demand over the owners, so owner and threshold changes never loop over proposals.
+    function confirmedAt(uint _txIndex) public view returns (uint) {
+        uint[] memory times = new uint[](owners.length);
+        uint count;
+        for (uint i; i < owners.length; i++) {
+            if (!isConfirmed(_txIndex, owners[i])) {
+                continue;
+            }
+            uint time = _confirmations[_txIndex][owners[i]].time;
+            uint j = count++;
+            for (; j > 0 && times[j - 1] > time; j--) { // Keep times sorted
+                times[j] = times[j - 1];
+            }
+            times[j] = time;
+        }
+        if (count < requiredConfirmations) {
+            return 0;
+        }
+        uint reached = times[requiredConfirmations - 1];
+        return reached > _requirementChangedAt ? reached : _requirementChangedAt;
+    }
+
+    // Earliest time a proposal can be executed, 0 while it lacks confirmations. Only plain ETH
+    // transfers below timelockMinValue skip the delay: any call with data waits, whatever its value,
+    // since it can move tokens or change the wallet itself (a timelock change included).
+    function executableAt(uint _txIndex) public view txExists(_txIndex) returns (uint) {
+        uint reached = confirmedAt(_txIndex);
+        Transaction storage txn = _transactions[_txIndex];
+        if (reached == 0 || (txn.value < timelockMinValue && txn.data.length == 0)) {
+            return reached;
+        }
+        return reached + timelockDelay;
+    }
+
+    // Confirmations of current owners
+    function _confirmationCount(uint _txIndex) private view returns (uint count) {
+        for (uint i; i < owners.length; i++) {
+            if (isConfirmed(_txIndex, owners[i])) {
+                count += 1;
+            }
+        }
+    }
+
     function getTransactionCount() public view returns (uint) {
-        return transactions.length;
+        return _transactions.length;
     }
 
     function getTransaction(uint _txIndex)
@@ -112,7 +260,15 @@ contract MultisigWallet {
         view
         returns (address to, uint value, bytes memory data, bool executed, uint confirmations)
     {
-        Transaction storage txn = transactions[_txIndex];
-        return (txn.to, txn.value, txn.data, txn.executed, txn.confirmations);
+        return transactions(_txIndex);
+    }
+
+    function transactions(uint _txIndex)
+        public
+        view
+        returns (address to, uint value, bytes memory data, bool executed, uint confirmations)
+    {
+        Transaction storage txn = _transactions[_txIndex];
+        return (txn.to, txn.value, txn.data, txn.executed, _confirmationCount(_txIndex));
     }
 }
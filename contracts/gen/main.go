@@ -0,0 +1,136 @@
+// gen compiles MultisigWallet.sol with the pinned solc release and writes the contract artifacts:
+// multisigwallet.abi, multisig.bin (the creation code) and the Go bindings in multisig.go. It runs
+// in the contracts directory through `go generate ./contracts` and needs that solc release on PATH
+// (or in $SOLC). The compiler settings below are part of the pinned build: changing them changes
+// the bytecode.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/abigen"
+)
+
+const (
+	solcVersion = "0.8.30+commit.73712a01" // Pinned compiler release
+	source      = "MultisigWallet.sol"
+	contract    = "MultisigWallet"
+)
+
+// settings are the solc standard-JSON settings of the pinned build
+var settings = map[string]any{
+	"optimizer":  map[string]any{"enabled": true, "runs": 200},
+	"evmVersion": "cancun",
+	"outputSelection": map[string]any{
+		"*": map[string]any{"*": []string{"abi", "evm.bytecode.object"}},
+	},
+}
+
+// output is the part of the solc standard-JSON output the generator reads
+type output struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		FormattedMessage string `json:"formattedMessage"`
+	} `json:"errors"`
+	Contracts map[string]map[string]struct {
+		ABI json.RawMessage `json:"abi"`
+		EVM struct {
+			Bytecode struct {
+				Object string `json:"object"`
+			} `json:"bytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gen: ")
+
+	solc := os.Getenv("SOLC")
+	if solc == "" {
+		solc = "solc"
+	}
+	version, err := exec.Command(solc, "--version").Output()
+	if err != nil {
+		log.Fatalf("running %s --version: %v", solc, err)
+	}
+	if !strings.Contains(string(version), "Version: "+solcVersion) {
+		log.Fatalf("%s is not solc %s:\n%s", solc, solcVersion, version)
+	}
+
+	abiJSON, bin, err := compile(solc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, abiJSON, "", "\t"); err != nil {
+		log.Fatal(err)
+	}
+	code, err := abigen.Bind([]string{"Contracts"}, []string{string(abiJSON)}, []string{bin}, nil, "contracts", nil, nil)
+	if err != nil {
+		log.Fatalf("generating bindings: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"multisigwallet.abi": indented.Bytes(),
+		"multisig.bin":       []byte(bin),
+		"multisig.go":        []byte(code),
+	} {
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	log.Printf("%s: %d bytes of creation code", contract, len(bin)/2)
+}
+
+// compile runs solc on the contract source and returns its ABI and creation code in hex
+func compile(solc string) (json.RawMessage, string, error) {
+	src, err := os.ReadFile(source)
+	if err != nil {
+		return nil, "", err
+	}
+	input, err := json.Marshal(map[string]any{
+		"language": "Solidity",
+		"sources":  map[string]any{source: map[string]string{"content": string(src)}},
+		"settings": settings,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	cmd := exec.Command(solc, "--standard-json")
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	raw, err := cmd.Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("running solc: %v", err)
+	}
+	var out output
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, "", fmt.Errorf("reading solc output: %v", err)
+	}
+
+	// Warnings fail the build too: the committed contract compiles cleanly
+	var problems []string
+	for _, e := range out.Errors {
+		if e.Severity != "info" {
+			problems = append(problems, e.FormattedMessage)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, "", errors.New(strings.Join(problems, "\n"))
+	}
+	c, ok := out.Contracts[source][contract]
+	if !ok || c.EVM.Bytecode.Object == "" {
+		return nil, "", fmt.Errorf("solc produced no bytecode for %s", contract)
+	}
+	return c.ABI, c.EVM.Bytecode.Object, nil
+}
@@ -0,0 +1,9 @@
+// Package contracts holds the MultisigWallet contract and its generated Go bindings.
+//
+// multisigwallet.abi, multisig.bin and multisig.go are generated from MultisigWallet.sol; do not edit
+// them by hand. Regenerate them after changing the contract with solc 0.8.30 on PATH:
+//
+//	go generate ./contracts
+package contracts
+
+//go:generate go run ./gen
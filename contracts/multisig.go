@@ -31,13 +31,35 @@ var (
 
 // ContractsMetaData contains all meta data concerning the Contracts contract.
 var ContractsMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"confirmTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address[]\",\"name\":\"_owners\",\"type\":\"address[]\"},{\"internalType\":\"uint256\",\"name\":\"_requiredConfirmations\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"}],\"name\":\"ConfirmTransaction\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Deposit\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"executeTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"}],\"name\":\"ExecuteTransaction\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"_value\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"}],\"name\":\"submitTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"SubmitTransaction\",\"type\":\"event\"},{\"stateMutability\":\"payable\",\"type\":\"receive\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"getTransaction\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"},{\"internalType\":\"bool\",\"name\":\"executed\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"confirmations\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getTransactionCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"isConfirmed\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"isOwner\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"owners\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"requiredConfirmations\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"transactions\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"},{\"internalType\":\"bool\",\"name\":\"executed\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"confirmations\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	ABI: "[{\"inputs\":[{\"internalType\":\"address[]\",\"name\":\"_owners\",\"type\":\"address[]\"},{\"internalType\":\"uint256\",\"name\":\"_requiredConfirmations\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"}],\"name\":\"ConfirmTransaction\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Deposit\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"}],\"name\":\"ExecuteTransaction\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnerAddition\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnerRemoval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"requiredConfirmations\",\"type\":\"uint256\"}],\"name\":\"RequirementChange\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"}],\"name\":\"RevokeConfirmation\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"txIndex\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"SubmitTransaction\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"delay\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"minValue\",\"type\":\"uint256\"}],\"name\":\"TimelockChange\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"addOwner\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_requiredConfirmations\",\"type\":\"uint256\"}],\"name\":\"changeRequirement\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_delay\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_minValue\",\"type\":\"uint256\"}],\"name\":\"changeTimelock\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"confirmTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"confirmedAt\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"executableAt\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"executeTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"getTransaction\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"},{\"internalType\":\"bool\",\"name\":\"executed\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"confirmations\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getTransactionCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"isConfirmed\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"isOwner\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"owners\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_owner\",\"type\":\"address\"}],\"name\":\"removeOwner\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_newOwner\",\"type\":\"address\"}],\"name\":\"replaceOwner\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"requiredConfirmations\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"revokeConfirmation\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"_value\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"}],\"name\":\"submitTransaction\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"timelockDelay\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"timelockMinValue\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_txIndex\",\"type\":\"uint256\"}],\"name\":\"transactions\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"},{\"internalType\":\"bool\",\"name\":\"executed\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"confirmations\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"stateMutability\":\"payable\",\"type\":\"receive\"}]",
+	Bin: "0x608060405234801561000f575f5ffd5b50604051611d6d380380611d6d83398101604081905261002e9161023d565b5f8251116100755760405162461bcd60e51b815260206004820152600f60248201526e1bdddb995c9cc81c995c5d5a5c9959608a1b60448201526064015b60405180910390fd5b5f81118015610085575081518111155b6100d15760405162461bcd60e51b815260206004820152601560248201527f696e76616c696420636f6e6669726d6174696f6e730000000000000000000000604482015260640161006c565b5f5b8251811015610204575f8382815181106100ef576100ef610311565b602002602001015190505f6001600160a01b0316816001600160a01b03161415801561013357506001600160a01b0381165f9081526001602052604090205460ff16155b61016f5760405162461bcd60e51b815260206004820152600d60248201526c34b73b30b634b21037bbb732b960991b604482015260640161006c565b6001600160a01b0381165f908152600160208181526040808420805460ff191684179055600590915282208054919290916101ab908490610325565b90915550505f8054600180820183559180527f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e5630180546001600160a01b0319166001600160a01b039390931692909217909155016100d3565b506002555061034a565b634e487b7160e01b5f52604160045260245ffd5b80516001600160a01b0381168114610238575f5ffd5b919050565b5f5f6040838503121561024e575f5ffd5b82516001600160401b03811115610263575f5ffd5b8301601f81018513610273575f5ffd5b80516001600160401b0381111561028c5761028c61020e565b604051600582901b90603f8201601f191681016001600160401b03811182821017156102ba576102ba61020e565b6040529182526020818401810192908101888411156102d7575f5ffd5b6020850194505b838510156102fd576102ef85610222565b8152602094850194016102de565b506020969096015195979596505050505050565b634e487b7160e01b5f52603260045260245ffd5b8082018082111561034457634e487b7160e01b5f52601160045260245ffd5b92915050565b611a16806103575f395ff3fe60806040526004361061011e575f3560e01c806380f59a651161009d578063c01a8c8411610062578063c01a8c841461036d578063c64274741461038c578063e20056e6146103ab578063ee22610b146103ca578063eef09bad146103e9575f5ffd5b806380f59a65146102dc57806382e717f7146102fb5780638bf4cc02146103105780639ace38c21461032f578063ba51a6df1461034e575f5ffd5b80632b71f909116100e35780632b71f9091461021c5780632e7700f01461023b5780632f54bf6e1461024f57806333ea3dc81461028d5780637065cb48146102bd575f5ffd5b8063025e7c271461015e578063173825d91461019a57806320d0adcb146101bb57806320ea8d86146101e85780632abbf74914610207575f5ffd5b3661015a5760405134815233907fe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c9060200160405180910390a2005b5f5ffd5b348015610169575f5ffd5b5061017d6101783660046114ed565b6103fe565b6040516001600160a01b0390911681526020015b60405180910390f35b3480156101a5575f5ffd5b506101b96101b436600461151f565b610425565b005b3480156101c6575f5ffd5b506101da6101d53660046114ed565b61061a565b604051908152602001610191565b3480156101f3575f5ffd5b506101b96102023660046114ed565b6106be565b348015610212575f5ffd5b506101da60075481565b348015610227575f5ffd5b506101b9610236366004611538565b6107ec565b348015610246575f5ffd5b506003546101da565b34801561025a575f5ffd5b5061027d61026936600461151f565b60016020525f908152604090205460ff1681565b6040519015158152602001610191565b348015610298575f5ffd5b506102ac6102a73660046114ed565b610852565b604051610191959493929190611586565b3480156102c8575f5ffd5b506101b96102d736600461151f565b610873565b3480156102e7575f5ffd5b5061027d6102f63660046115c0565b6109b4565b348015610306575f5ffd5b506101da60025481565b34801561031b575f5ffd5b506101da61032a3660046114ed565b610a10565b34801561033a575f5ffd5b506102ac6103493660046114ed565b610bff565b348015610359575f5ffd5b506101b96103683660046114ed565b610cf2565b348015610378575f5ffd5b506101b96103873660046114ed565b610da4565b348015610397575f5ffd5b506101b96103a63660046115fe565b610ef9565b3480156103b6575f5ffd5b506101b96103c53660046116ca565b611068565b3480156103d5575f5ffd5b506101b96103e43660046114ed565b611274565b3480156103f4575f5ffd5b506101da60065481565b5f818154811061040c575f80fd5b5f918252602090912001546001600160a01b0316905081565b33301461044d5760405162461bcd60e51b8152600401610444906116f2565b60405180910390fd5b6001600160a01b0381165f9081526001602052604090205460ff166104845760405162461bcd60e51b815260040161044490611717565b6002545f546104959060019061174e565b10156104d95760405162461bcd60e51b81526020600482015260136024820152721d1bdbc819995dc81bdddb995c9cc81b19599d606a1b6044820152606401610444565b6001600160a01b0381165f908152600160205260408120805460ff191690555b5f548110156105e357816001600160a01b03165f828154811061051e5761051e611761565b5f918252602090912001546001600160a01b0316036105db575f80546105469060019061174e565b8154811061055657610556611761565b5f91825260208220015481546001600160a01b0390911691908390811061057f5761057f611761565b5f918252602082200180546001600160a01b0319166001600160a01b0393909316929092179091558054806105b6576105b6611775565b5f8281526020902081015f1990810180546001600160a01b03191690550190556105e3565b6001016104f9565b506040516001600160a01b038216907f8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90905f90a250565b6003545f908290811061063f5760405162461bcd60e51b815260040161044490611789565b5f61064984610a10565b90505f6003858154811061065f5761065f611761565b905f5260205f2090600402019050815f1480610699575060075481600101541080156106995750806002018054610695906117b4565b1590505b156106a6575091506106b8565b6006546106b390836117e6565b935050505b50919050565b335f9081526001602052604090205460ff166106ec5760405162461bcd60e51b815260040161044490611717565b6003548190811061070f5760405162461bcd60e51b815260040161044490611789565b816003818154811061072357610723611761565b5f91825260209091206003600490920201015460ff16156107565760405162461bcd60e51b8152600401610444906117f9565b61076083336109b4565b61079f5760405162461bcd60e51b815260206004820152601060248201526f1d1e081b9bdd0818dbdb999a5c9b595960821b6044820152606401610444565b5f8381526004602090815260408083203380855292528083208381556001018390555185927ff0dca620e2e81f7841d07bcc105e1704fb01475b278a9d4c236e1c62945edd5591a3505050565b33301461080b5760405162461bcd60e51b8152600401610444906116f2565b6006829055600781905560408051838152602081018390527fab9b24574eb9cb445b5a80e9f2167e93a340cb365344515be9ed471fc5fe69dc910160405180910390a15050565b5f5f60605f5f61086186610bff565b939a9299509097509550909350915050565b3330146108925760405162461bcd60e51b8152600401610444906116f2565b6001600160a01b038116158015906108c257506001600160a01b0381165f9081526001602052604090205460ff16155b6108fe5760405162461bcd60e51b815260206004820152600d60248201526c34b73b30b634b21037bbb732b960991b6044820152606401610444565b6001600160a01b0381165f908152600160208181526040808420805460ff1916841790556005909152822080549192909161093a9084906117e6565b90915550505f80546001810182558180527f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e5630180546001600160a01b0319166001600160a01b03841690811790915560405190917ff39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d91a250565b6001600160a01b0381165f9081526001602052604081205460ff168015610a0757506001600160a01b0382165f818152600560209081526040808320548784526004835281842094845293909152902054145b90505b92915050565b5f8054819067ffffffffffffffff811115610a2d57610a2d6115ea565b604051908082528060200260200182016040528015610a56578160200160208202803683370190505b5090505f5f5b5f54811015610ba757610a94855f8381548110610a7b57610a7b611761565b5f918252602090912001546001600160a01b03166109b4565b15610b9f575f85815260046020526040812081548290819085908110610abc57610abc611761565b5f9182526020808320909101546001600160a01b03168352820192909252604001812060010154915083610aef81611823565b945090505b5f81118015610b2557508185610b0b60018461174e565b81518110610b1b57610b1b611761565b6020026020010151115b15610b7d5784610b3660018361174e565b81518110610b4657610b46611761565b6020026020010151858281518110610b6057610b60611761565b602090810291909101015280610b758161183b565b915050610af4565b81858281518110610b9057610b90611761565b60200260200101818152505050505b600101610a5c565b50600254811015610bbb57505f9392505050565b5f826001600254610bcc919061174e565b81518110610bdc57610bdc611761565b602002602001015190506008548111610bf7576008546106b3565b949350505050565b5f5f60605f5f5f60038781548110610c1957610c19611761565b5f91825260209091206004909102018054600182015460038301549293506001600160a01b0390911691600284019060ff16610c548b6114b0565b828054610c60906117b4565b80601f0160208091040260200160405190810160405280929190818152602001828054610c8c906117b4565b8015610cd75780601f10610cae57610100808354040283529160200191610cd7565b820191905f5260205f20905b815481529060010190602001808311610cba57829003601f168201915b50505050509250955095509550955095505091939590929450565b333014610d115760405162461bcd60e51b8152600401610444906116f2565b5f81118015610d2157505f548111155b610d655760405162461bcd60e51b8152602060048201526015602482015274696e76616c696420636f6e6669726d6174696f6e7360581b6044820152606401610444565b6002819055426008556040518181527fa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a9060200160405180910390a150565b335f9081526001602052604090205460ff16610dd25760405162461bcd60e51b815260040161044490611717565b60035481908110610df55760405162461bcd60e51b815260040161044490611789565b81610e0081336109b4565b15610e415760405162461bcd60e51b8152602060048201526011602482015270185b1c9958591e4818dbdb999a5c9b5959607a1b6044820152606401610444565b8260038181548110610e5557610e55611761565b5f91825260209091206003600490920201015460ff1615610e885760405162461bcd60e51b8152600401610444906117f9565b604080518082018252335f818152600560209081528482205484524281850190815289835260048252858320848452909152848220935184555160019093019290925591518692917f5cbe105e36805f7820e291f799d5794ff948af2a5f664e580382defb6339004191a350505050565b335f9081526001602052604090205460ff16610f275760405162461bcd60e51b815260040161044490611717565b604080516080810182526001600160a01b038581168252602082018581529282018481525f6060840181905260038054600181018255915283517fc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85b600490920291820180546001600160a01b0319169190941617835593517fc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85c85015551919290917fc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85d90910190610ff7908261189c565b50606091909101516003918201805460ff1916911515919091179055546001600160a01b0384169061102b9060019061174e565b7ec2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe848460405161105b929190611957565b60405180910390a3505050565b3330146110875760405162461bcd60e51b8152600401610444906116f2565b6001600160a01b0382165f9081526001602052604090205460ff166110be5760405162461bcd60e51b815260040161044490611717565b6001600160a01b038116158015906110ee57506001600160a01b0381165f9081526001602052604090205460ff16155b61112a5760405162461bcd60e51b815260206004820152600d60248201526c34b73b30b634b21037bbb732b960991b6044820152606401610444565b5f5b5f548110156111b557826001600160a01b03165f828154811061115157611151611761565b5f918252602090912001546001600160a01b0316036111ad57815f828154811061117d5761117d611761565b905f5260205f20015f6101000a8154816001600160a01b0302191690836001600160a01b031602179055506111b5565b60010161112c565b506001600160a01b038083165f908152600160208181526040808420805460ff199081169091559486168452808420805490951683179094556005905291812080549091906112059084906117e6565b90915550506040516001600160a01b038316907f8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90905f90a26040516001600160a01b038216907ff39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d905f90a25050565b335f9081526001602052604090205460ff166112a25760405162461bcd60e51b815260040161044490611717565b600354819081106112c55760405162461bcd60e51b815260040161044490611789565b81600381815481106112d9576112d9611761565b5f91825260209091206003600490920201015460ff161561130c5760405162461bcd60e51b8152600401610444906117f9565b5f6003848154811061132057611320611761565b905f5260205f209060040201905060025461133a856114b0565b10156113885760405162461bcd60e51b815260206004820152601860248201527f6e6f7420656e6f75676820636f6e6669726d6174696f6e7300000000000000006044820152606401610444565b6113918461061a565b4210156113cd5760405162461bcd60e51b815260206004820152600a6024820152691d1a5b595b1bd8dad95960b21b6044820152606401610444565b60038101805460ff191660019081179091558154908201546040515f926001600160a01b0316919061140390600286019061196f565b5f6040518083038185875af1925050503d805f811461143d576040519150601f19603f3d011682016040523d82523d5f602084013e611442565b606091505b505090508061147f5760405162461bcd60e51b81526020600482015260096024820152681d1e0819985a5b195960ba1b6044820152606401610444565b60405185907fae30dc3f11bb6b178aafe5e7fc568fb6d87200068a944a8015c0db1b4533dbb8905f90a25050505050565b5f5f5b5f548110156106b8576114d2835f8381548110610a7b57610a7b611761565b156114e5576114e26001836117e6565b91505b6001016114b3565b5f602082840312156114fd575f5ffd5b5035919050565b80356001600160a01b038116811461151a575f5ffd5b919050565b5f6020828403121561152f575f5ffd5b610a0782611504565b5f5f60408385031215611549575f5ffd5b50508035926020909101359150565b5f81518084528060208401602086015e5f602082860101526020601f19601f83011685010191505092915050565b60018060a01b038616815284602082015260a060408201525f6115ac60a0830186611558565b931515606083015250608001529392505050565b5f5f604083850312156115d1575f5ffd5b823591506115e160208401611504565b90509250929050565b634e487b7160e01b5f52604160045260245ffd5b5f5f5f60608486031215611610575f5ffd5b61161984611504565b925060208401359150604084013567ffffffffffffffff81111561163b575f5ffd5b8401601f8101861361164b575f5ffd5b803567ffffffffffffffff811115611665576116656115ea565b604051601f8201601f19908116603f0116810167ffffffffffffffff81118282101715611694576116946115ea565b6040528181528282016020018810156116ab575f5ffd5b816020840160208301375f602083830101528093505050509250925092565b5f5f604083850312156116db575f5ffd5b6116e483611504565b91506115e160208401611504565b6020808252600b908201526a1bdb9b1e481dd85b1b195d60aa1b604082015260600190565b6020808252600990820152683737ba1037bbb732b960b91b604082015260600190565b634e487b7160e01b5f52601160045260245ffd5b81810381811115610a0a57610a0a61173a565b634e487b7160e01b5f52603260045260245ffd5b634e487b7160e01b5f52603160045260245ffd5b6020808252601190820152701d1e08191bd95cc81b9bdd08195e1a5cdd607a1b604082015260600190565b600181811c908216806117c857607f821691505b6020821081036106b857634e487b7160e01b5f52602260045260245ffd5b80820180821115610a0a57610a0a61173a565b60208082526010908201526f185b1c9958591e48195e1958dd5d195960821b604082015260600190565b5f600182016118345761183461173a565b5060010190565b5f816118495761184961173a565b505f190190565b601f82111561189757805f5260205f20601f840160051c810160208510156118755750805b601f840160051c820191505b81811015611894575f8155600101611881565b50505b505050565b815167ffffffffffffffff8111156118b6576118b66115ea565b6118ca816118c484546117b4565b84611850565b6020601f8211600181146118fc575f83156118e55750848201515b5f19600385901b1c1916600184901b178455611894565b5f84815260208120601f198516915b8281101561192b578785015182556020948501946001909201910161190b565b508482101561194857868401515f19600387901b60f8161c191681555b50505050600190811b01905550565b828152604060208201525f610bf76040830184611558565b5f5f835461197c816117b4565b60018216801561199357600181146119a8576119d5565b60ff19831686528115158202860193506119d5565b865f5260205f205f5b838110156119cd578154888201526001909101906020016119b1565b505081860193505b50919594505050505056fea2646970667358221220334075a86434a6e4ff2a3e65bc1f6b0864ad4e1bcc8201e7db7f8f83af43fcaf64736f6c634300081e0033",
 }
 
 // ContractsABI is the input ABI used to generate the binding from.
 // Deprecated: Use ContractsMetaData.ABI instead.
 var ContractsABI = ContractsMetaData.ABI
 
+// ContractsBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use ContractsMetaData.Bin instead.
+var ContractsBin = ContractsMetaData.Bin
+
+// DeployContracts deploys a new Ethereum contract, binding an instance of Contracts to it.
+func DeployContracts(auth *bind.TransactOpts, backend bind.ContractBackend, _owners []common.Address, _requiredConfirmations *big.Int) (common.Address, *types.Transaction, *Contracts, error) {
+	parsed, err := ContractsMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(ContractsBin), backend, _owners, _requiredConfirmations)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &Contracts{ContractsCaller: ContractsCaller{contract: contract}, ContractsTransactor: ContractsTransactor{contract: contract}, ContractsFilterer: ContractsFilterer{contract: contract}}, nil
+}
+
 // Contracts is an auto generated Go binding around an Ethereum contract.
 type Contracts struct {
 	ContractsCaller     // Read-only binding to the contract
@@ -180,6 +202,68 @@ func (_Contracts *ContractsTransactorRaw) Transact(opts *bind.TransactOpts, meth
 	return _Contracts.Contract.contract.Transact(opts, method, params...)
 }
 
+// ConfirmedAt is a free data retrieval call binding the contract method 0x8bf4cc02.
+//
+// Solidity: function confirmedAt(uint256 _txIndex) view returns(uint256)
+func (_Contracts *ContractsCaller) ConfirmedAt(opts *bind.CallOpts, _txIndex *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _Contracts.contract.Call(opts, &out, "confirmedAt", _txIndex)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ConfirmedAt is a free data retrieval call binding the contract method 0x8bf4cc02.
+//
+// Solidity: function confirmedAt(uint256 _txIndex) view returns(uint256)
+func (_Contracts *ContractsSession) ConfirmedAt(_txIndex *big.Int) (*big.Int, error) {
+	return _Contracts.Contract.ConfirmedAt(&_Contracts.CallOpts, _txIndex)
+}
+
+// ConfirmedAt is a free data retrieval call binding the contract method 0x8bf4cc02.
+//
+// Solidity: function confirmedAt(uint256 _txIndex) view returns(uint256)
+func (_Contracts *ContractsCallerSession) ConfirmedAt(_txIndex *big.Int) (*big.Int, error) {
+	return _Contracts.Contract.ConfirmedAt(&_Contracts.CallOpts, _txIndex)
+}
+
+// ExecutableAt is a free data retrieval call binding the contract method 0x20d0adcb.
+//
+// Solidity: function executableAt(uint256 _txIndex) view returns(uint256)
+func (_Contracts *ContractsCaller) ExecutableAt(opts *bind.CallOpts, _txIndex *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _Contracts.contract.Call(opts, &out, "executableAt", _txIndex)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ExecutableAt is a free data retrieval call binding the contract method 0x20d0adcb.
+//
+// Solidity: function executableAt(uint256 _txIndex) view returns(uint256)
+func (_Contracts *ContractsSession) ExecutableAt(_txIndex *big.Int) (*big.Int, error) {
+	return _Contracts.Contract.ExecutableAt(&_Contracts.CallOpts, _txIndex)
+}
+
+// ExecutableAt is a free data retrieval call binding the contract method 0x20d0adcb.
+//
+// Solidity: function executableAt(uint256 _txIndex) view returns(uint256)
+func (_Contracts *ContractsCallerSession) ExecutableAt(_txIndex *big.Int) (*big.Int, error) {
+	return _Contracts.Contract.ExecutableAt(&_Contracts.CallOpts, _txIndex)
+}
+
 // GetTransaction is a free data retrieval call binding the contract method 0x33ea3dc8.
 //
 // Solidity: function getTransaction(uint256 _txIndex) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
@@ -273,10 +357,10 @@ func (_Contracts *ContractsCallerSession) GetTransactionCount() (*big.Int, error
 
 // IsConfirmed is a free data retrieval call binding the contract method 0x80f59a65.
 //
-// Solidity: function isConfirmed(uint256 , address ) view returns(bool)
-func (_Contracts *ContractsCaller) IsConfirmed(opts *bind.CallOpts, arg0 *big.Int, arg1 common.Address) (bool, error) {
+// Solidity: function isConfirmed(uint256 _txIndex, address _owner) view returns(bool)
+func (_Contracts *ContractsCaller) IsConfirmed(opts *bind.CallOpts, _txIndex *big.Int, _owner common.Address) (bool, error) {
 	var out []interface{}
-	err := _Contracts.contract.Call(opts, &out, "isConfirmed", arg0, arg1)
+	err := _Contracts.contract.Call(opts, &out, "isConfirmed", _txIndex, _owner)
 
 	if err != nil {
 		return *new(bool), err
@@ -290,16 +374,16 @@ func (_Contracts *ContractsCaller) IsConfirmed(opts *bind.CallOpts, arg0 *big.In
 
 // IsConfirmed is a free data retrieval call binding the contract method 0x80f59a65.
 //
-// Solidity: function isConfirmed(uint256 , address ) view returns(bool)
-func (_Contracts *ContractsSession) IsConfirmed(arg0 *big.Int, arg1 common.Address) (bool, error) {
-	return _Contracts.Contract.IsConfirmed(&_Contracts.CallOpts, arg0, arg1)
+// Solidity: function isConfirmed(uint256 _txIndex, address _owner) view returns(bool)
+func (_Contracts *ContractsSession) IsConfirmed(_txIndex *big.Int, _owner common.Address) (bool, error) {
+	return _Contracts.Contract.IsConfirmed(&_Contracts.CallOpts, _txIndex, _owner)
 }
 
 // IsConfirmed is a free data retrieval call binding the contract method 0x80f59a65.
 //
-// Solidity: function isConfirmed(uint256 , address ) view returns(bool)
-func (_Contracts *ContractsCallerSession) IsConfirmed(arg0 *big.Int, arg1 common.Address) (bool, error) {
-	return _Contracts.Contract.IsConfirmed(&_Contracts.CallOpts, arg0, arg1)
+// Solidity: function isConfirmed(uint256 _txIndex, address _owner) view returns(bool)
+func (_Contracts *ContractsCallerSession) IsConfirmed(_txIndex *big.Int, _owner common.Address) (bool, error) {
+	return _Contracts.Contract.IsConfirmed(&_Contracts.CallOpts, _txIndex, _owner)
 }
 
 // IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
@@ -395,10 +479,72 @@ func (_Contracts *ContractsCallerSession) RequiredConfirmations() (*big.Int, err
 	return _Contracts.Contract.RequiredConfirmations(&_Contracts.CallOpts)
 }
 
+// TimelockDelay is a free data retrieval call binding the contract method 0xeef09bad.
+//
+// Solidity: function timelockDelay() view returns(uint256)
+func (_Contracts *ContractsCaller) TimelockDelay(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Contracts.contract.Call(opts, &out, "timelockDelay")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TimelockDelay is a free data retrieval call binding the contract method 0xeef09bad.
+//
+// Solidity: function timelockDelay() view returns(uint256)
+func (_Contracts *ContractsSession) TimelockDelay() (*big.Int, error) {
+	return _Contracts.Contract.TimelockDelay(&_Contracts.CallOpts)
+}
+
+// TimelockDelay is a free data retrieval call binding the contract method 0xeef09bad.
+//
+// Solidity: function timelockDelay() view returns(uint256)
+func (_Contracts *ContractsCallerSession) TimelockDelay() (*big.Int, error) {
+	return _Contracts.Contract.TimelockDelay(&_Contracts.CallOpts)
+}
+
+// TimelockMinValue is a free data retrieval call binding the contract method 0x2abbf749.
+//
+// Solidity: function timelockMinValue() view returns(uint256)
+func (_Contracts *ContractsCaller) TimelockMinValue(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Contracts.contract.Call(opts, &out, "timelockMinValue")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TimelockMinValue is a free data retrieval call binding the contract method 0x2abbf749.
+//
+// Solidity: function timelockMinValue() view returns(uint256)
+func (_Contracts *ContractsSession) TimelockMinValue() (*big.Int, error) {
+	return _Contracts.Contract.TimelockMinValue(&_Contracts.CallOpts)
+}
+
+// TimelockMinValue is a free data retrieval call binding the contract method 0x2abbf749.
+//
+// Solidity: function timelockMinValue() view returns(uint256)
+func (_Contracts *ContractsCallerSession) TimelockMinValue() (*big.Int, error) {
+	return _Contracts.Contract.TimelockMinValue(&_Contracts.CallOpts)
+}
+
 // Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
 //
-// Solidity: function transactions(uint256 ) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
-func (_Contracts *ContractsCaller) Transactions(opts *bind.CallOpts, arg0 *big.Int) (struct {
+// Solidity: function transactions(uint256 _txIndex) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
+func (_Contracts *ContractsCaller) Transactions(opts *bind.CallOpts, _txIndex *big.Int) (struct {
 	To            common.Address
 	Value         *big.Int
 	Data          []byte
@@ -406,7 +552,7 @@ func (_Contracts *ContractsCaller) Transactions(opts *bind.CallOpts, arg0 *big.I
 	Confirmations *big.Int
 }, error) {
 	var out []interface{}
-	err := _Contracts.contract.Call(opts, &out, "transactions", arg0)
+	err := _Contracts.contract.Call(opts, &out, "transactions", _txIndex)
 
 	outstruct := new(struct {
 		To            common.Address
@@ -431,28 +577,91 @@ func (_Contracts *ContractsCaller) Transactions(opts *bind.CallOpts, arg0 *big.I
 
 // Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
 //
-// Solidity: function transactions(uint256 ) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
-func (_Contracts *ContractsSession) Transactions(arg0 *big.Int) (struct {
+// Solidity: function transactions(uint256 _txIndex) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
+func (_Contracts *ContractsSession) Transactions(_txIndex *big.Int) (struct {
 	To            common.Address
 	Value         *big.Int
 	Data          []byte
 	Executed      bool
 	Confirmations *big.Int
 }, error) {
-	return _Contracts.Contract.Transactions(&_Contracts.CallOpts, arg0)
+	return _Contracts.Contract.Transactions(&_Contracts.CallOpts, _txIndex)
 }
 
 // Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
 //
-// Solidity: function transactions(uint256 ) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
-func (_Contracts *ContractsCallerSession) Transactions(arg0 *big.Int) (struct {
+// Solidity: function transactions(uint256 _txIndex) view returns(address to, uint256 value, bytes data, bool executed, uint256 confirmations)
+func (_Contracts *ContractsCallerSession) Transactions(_txIndex *big.Int) (struct {
 	To            common.Address
 	Value         *big.Int
 	Data          []byte
 	Executed      bool
 	Confirmations *big.Int
 }, error) {
-	return _Contracts.Contract.Transactions(&_Contracts.CallOpts, arg0)
+	return _Contracts.Contract.Transactions(&_Contracts.CallOpts, _txIndex)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(address _owner) returns()
+func (_Contracts *ContractsTransactor) AddOwner(opts *bind.TransactOpts, _owner common.Address) (*types.Transaction, error) {
+	return _Contracts.contract.Transact(opts, "addOwner", _owner)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(address _owner) returns()
+func (_Contracts *ContractsSession) AddOwner(_owner common.Address) (*types.Transaction, error) {
+	return _Contracts.Contract.AddOwner(&_Contracts.TransactOpts, _owner)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(address _owner) returns()
+func (_Contracts *ContractsTransactorSession) AddOwner(_owner common.Address) (*types.Transaction, error) {
+	return _Contracts.Contract.AddOwner(&_Contracts.TransactOpts, _owner)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(uint256 _requiredConfirmations) returns()
+func (_Contracts *ContractsTransactor) ChangeRequirement(opts *bind.TransactOpts, _requiredConfirmations *big.Int) (*types.Transaction, error) {
+	return _Contracts.contract.Transact(opts, "changeRequirement", _requiredConfirmations)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(uint256 _requiredConfirmations) returns()
+func (_Contracts *ContractsSession) ChangeRequirement(_requiredConfirmations *big.Int) (*types.Transaction, error) {
+	return _Contracts.Contract.ChangeRequirement(&_Contracts.TransactOpts, _requiredConfirmations)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(uint256 _requiredConfirmations) returns()
+func (_Contracts *ContractsTransactorSession) ChangeRequirement(_requiredConfirmations *big.Int) (*types.Transaction, error) {
+	return _Contracts.Contract.ChangeRequirement(&_Contracts.TransactOpts, _requiredConfirmations)
+}
+
+// ChangeTimelock is a paid mutator transaction binding the contract method 0x2b71f909.
+//
+// Solidity: function changeTimelock(uint256 _delay, uint256 _minValue) returns()
+func (_Contracts *ContractsTransactor) ChangeTimelock(opts *bind.TransactOpts, _delay *big.Int, _minValue *big.Int) (*types.Transaction, error) {
+	return _Contracts.contract.Transact(opts, "changeTimelock", _delay, _minValue)
+}
+
+// ChangeTimelock is a paid mutator transaction binding the contract method 0x2b71f909.
+//
+// Solidity: function changeTimelock(uint256 _delay, uint256 _minValue) returns()
+func (_Contracts *ContractsSession) ChangeTimelock(_delay *big.Int, _minValue *big.Int) (*types.Transaction, error) {
+	return _Contracts.Contract.ChangeTimelock(&_Contracts.TransactOpts, _delay, _minValue)
+}
+
+// ChangeTimelock is a paid mutator transaction binding the contract method 0x2b71f909.
+//
+// Solidity: function changeTimelock(uint256 _delay, uint256 _minValue) returns()
+func (_Contracts *ContractsTransactorSession) ChangeTimelock(_delay *big.Int, _minValue *big.Int) (*types.Transaction, error) {
+	return _Contracts.Contract.ChangeTimelock(&_Contracts.TransactOpts, _delay, _minValue)
 }
 
 // ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
@@ -497,6 +706,69 @@ func (_Contracts *ContractsTransactorSession) ExecuteTransaction(_txIndex *big.I
 	return _Contracts.Contract.ExecuteTransaction(&_Contracts.TransactOpts, _txIndex)
 }
 
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(address _owner) returns()
+func (_Contracts *ContractsTransactor) RemoveOwner(opts *bind.TransactOpts, _owner common.Address) (*types.Transaction, error) {
+	return _Contracts.contract.Transact(opts, "removeOwner", _owner)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(address _owner) returns()
+func (_Contracts *ContractsSession) RemoveOwner(_owner common.Address) (*types.Transaction, error) {
+	return _Contracts.Contract.RemoveOwner(&_Contracts.TransactOpts, _owner)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(address _owner) returns()
+func (_Contracts *ContractsTransactorSession) RemoveOwner(_owner common.Address) (*types.Transaction, error) {
+	return _Contracts.Contract.RemoveOwner(&_Contracts.TransactOpts, _owner)
+}
+
+// ReplaceOwner is a paid mutator transaction binding the contract method 0xe20056e6.
+//
+// Solidity: function replaceOwner(address _owner, address _newOwner) returns()
+func (_Contracts *ContractsTransactor) ReplaceOwner(opts *bind.TransactOpts, _owner common.Address, _newOwner common.Address) (*types.Transaction, error) {
+	return _Contracts.contract.Transact(opts, "replaceOwner", _owner, _newOwner)
+}
+
+// ReplaceOwner is a paid mutator transaction binding the contract method 0xe20056e6.
+//
+// Solidity: function replaceOwner(address _owner, address _newOwner) returns()
+func (_Contracts *ContractsSession) ReplaceOwner(_owner common.Address, _newOwner common.Address) (*types.Transaction, error) {
+	return _Contracts.Contract.ReplaceOwner(&_Contracts.TransactOpts, _owner, _newOwner)
+}
+
+// ReplaceOwner is a paid mutator transaction binding the contract method 0xe20056e6.
+//
+// Solidity: function replaceOwner(address _owner, address _newOwner) returns()
+func (_Contracts *ContractsTransactorSession) ReplaceOwner(_owner common.Address, _newOwner common.Address) (*types.Transaction, error) {
+	return _Contracts.Contract.ReplaceOwner(&_Contracts.TransactOpts, _owner, _newOwner)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(uint256 _txIndex) returns()
+func (_Contracts *ContractsTransactor) RevokeConfirmation(opts *bind.TransactOpts, _txIndex *big.Int) (*types.Transaction, error) {
+	return _Contracts.contract.Transact(opts, "revokeConfirmation", _txIndex)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(uint256 _txIndex) returns()
+func (_Contracts *ContractsSession) RevokeConfirmation(_txIndex *big.Int) (*types.Transaction, error) {
+	return _Contracts.Contract.RevokeConfirmation(&_Contracts.TransactOpts, _txIndex)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(uint256 _txIndex) returns()
+func (_Contracts *ContractsTransactorSession) RevokeConfirmation(_txIndex *big.Int) (*types.Transaction, error) {
+	return _Contracts.Contract.RevokeConfirmation(&_Contracts.TransactOpts, _txIndex)
+}
+
 // SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
 //
 // Solidity: function submitTransaction(address _to, uint256 _value, bytes _data) returns()
@@ -981,9 +1253,9 @@ func (_Contracts *ContractsFilterer) ParseExecuteTransaction(log types.Log) (*Co
 	return event, nil
 }
 
-// ContractsSubmitTransactionIterator is returned from FilterSubmitTransaction and is used to iterate over the raw logs and unpacked data for SubmitTransaction events raised by the Contracts contract.
-type ContractsSubmitTransactionIterator struct {
-	Event *ContractsSubmitTransaction // Event containing the contract specifics and raw log
+// ContractsOwnerAdditionIterator is returned from FilterOwnerAddition and is used to iterate over the raw logs and unpacked data for OwnerAddition events raised by the Contracts contract.
+type ContractsOwnerAdditionIterator struct {
+	Event *ContractsOwnerAddition // Event containing the contract specifics and raw log
 
 	contract *bind.BoundContract // Generic contract to use for unpacking event data
 	event    string              // Event name to use for unpacking event data
@@ -997,7 +1269,7 @@ type ContractsSubmitTransactionIterator struct {
 // Next advances the iterator to the subsequent event, returning whether there
 // are any more events found. In case of a retrieval or parsing error, false is
 // returned and Error() can be queried for the exact failure.
-func (it *ContractsSubmitTransactionIterator) Next() bool {
+func (it *ContractsOwnerAdditionIterator) Next() bool {
 	// If the iterator failed, stop iterating
 	if it.fail != nil {
 		return false
@@ -1006,7 +1278,7 @@ func (it *ContractsSubmitTransactionIterator) Next() bool {
 	if it.done {
 		select {
 		case log := <-it.logs:
-			it.Event = new(ContractsSubmitTransaction)
+			it.Event = new(ContractsOwnerAddition)
 			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 				it.fail = err
 				return false
@@ -1021,7 +1293,7 @@ func (it *ContractsSubmitTransactionIterator) Next() bool {
 	// Iterator still in progress, wait for either a data or an error event
 	select {
 	case log := <-it.logs:
-		it.Event = new(ContractsSubmitTransaction)
+		it.Event = new(ContractsOwnerAddition)
 		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
 			it.fail = err
 			return false
@@ -1037,62 +1309,51 @@ func (it *ContractsSubmitTransactionIterator) Next() bool {
 }
 
 // Error returns any retrieval or parsing error occurred during filtering.
-func (it *ContractsSubmitTransactionIterator) Error() error {
+func (it *ContractsOwnerAdditionIterator) Error() error {
 	return it.fail
 }
 
 // Close terminates the iteration process, releasing any pending underlying
 // resources.
-func (it *ContractsSubmitTransactionIterator) Close() error {
+func (it *ContractsOwnerAdditionIterator) Close() error {
 	it.sub.Unsubscribe()
 	return nil
 }
 
-// ContractsSubmitTransaction represents a SubmitTransaction event raised by the Contracts contract.
-type ContractsSubmitTransaction struct {
-	TxIndex *big.Int
-	To      common.Address
-	Value   *big.Int
-	Data    []byte
-	Raw     types.Log // Blockchain specific contextual infos
+// ContractsOwnerAddition represents a OwnerAddition event raised by the Contracts contract.
+type ContractsOwnerAddition struct {
+	Owner common.Address
+	Raw   types.Log // Blockchain specific contextual infos
 }
 
-// FilterSubmitTransaction is a free log retrieval operation binding the contract event 0x00c2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe.
+// FilterOwnerAddition is a free log retrieval operation binding the contract event 0xf39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d.
 //
-// Solidity: event SubmitTransaction(uint256 indexed txIndex, address indexed to, uint256 value, bytes data)
-func (_Contracts *ContractsFilterer) FilterSubmitTransaction(opts *bind.FilterOpts, txIndex []*big.Int, to []common.Address) (*ContractsSubmitTransactionIterator, error) {
+// Solidity: event OwnerAddition(address indexed owner)
+func (_Contracts *ContractsFilterer) FilterOwnerAddition(opts *bind.FilterOpts, owner []common.Address) (*ContractsOwnerAdditionIterator, error) {
 
-	var txIndexRule []interface{}
-	for _, txIndexItem := range txIndex {
-		txIndexRule = append(txIndexRule, txIndexItem)
-	}
-	var toRule []interface{}
-	for _, toItem := range to {
-		toRule = append(toRule, toItem)
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
 	}
 
-	logs, sub, err := _Contracts.contract.FilterLogs(opts, "SubmitTransaction", txIndexRule, toRule)
+	logs, sub, err := _Contracts.contract.FilterLogs(opts, "OwnerAddition", ownerRule)
 	if err != nil {
 		return nil, err
 	}
-	return &ContractsSubmitTransactionIterator{contract: _Contracts.contract, event: "SubmitTransaction", logs: logs, sub: sub}, nil
+	return &ContractsOwnerAdditionIterator{contract: _Contracts.contract, event: "OwnerAddition", logs: logs, sub: sub}, nil
 }
 
-// WatchSubmitTransaction is a free log subscription operation binding the contract event 0x00c2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe.
+// WatchOwnerAddition is a free log subscription operation binding the contract event 0xf39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d.
 //
-// Solidity: event SubmitTransaction(uint256 indexed txIndex, address indexed to, uint256 value, bytes data)
-func (_Contracts *ContractsFilterer) WatchSubmitTransaction(opts *bind.WatchOpts, sink chan<- *ContractsSubmitTransaction, txIndex []*big.Int, to []common.Address) (event.Subscription, error) {
+// Solidity: event OwnerAddition(address indexed owner)
+func (_Contracts *ContractsFilterer) WatchOwnerAddition(opts *bind.WatchOpts, sink chan<- *ContractsOwnerAddition, owner []common.Address) (event.Subscription, error) {
 
-	var txIndexRule []interface{}
-	for _, txIndexItem := range txIndex {
-		txIndexRule = append(txIndexRule, txIndexItem)
-	}
-	var toRule []interface{}
-	for _, toItem := range to {
-		toRule = append(toRule, toItem)
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
 	}
 
-	logs, sub, err := _Contracts.contract.WatchLogs(opts, "SubmitTransaction", txIndexRule, toRule)
+	logs, sub, err := _Contracts.contract.WatchLogs(opts, "OwnerAddition", ownerRule)
 	if err != nil {
 		return nil, err
 	}
@@ -1102,8 +1363,8 @@ func (_Contracts *ContractsFilterer) WatchSubmitTransaction(opts *bind.WatchOpts
 			select {
 			case log := <-logs:
 				// New log arrived, parse the event and forward to the user
-				event := new(ContractsSubmitTransaction)
-				if err := _Contracts.contract.UnpackLog(event, "SubmitTransaction", log); err != nil {
+				event := new(ContractsOwnerAddition)
+				if err := _Contracts.contract.UnpackLog(event, "OwnerAddition", log); err != nil {
 					return err
 				}
 				event.Raw = log
@@ -1124,12 +1385,733 @@ func (_Contracts *ContractsFilterer) WatchSubmitTransaction(opts *bind.WatchOpts
 	}), nil
 }
 
-// ParseSubmitTransaction is a log parse operation binding the contract event 0x00c2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe.
+// ParseOwnerAddition is a log parse operation binding the contract event 0xf39e6e1eb0edcf53c221607b54b00cd28f3196fed0a24994dc308b8f611b682d.
 //
-// Solidity: event SubmitTransaction(uint256 indexed txIndex, address indexed to, uint256 value, bytes data)
-func (_Contracts *ContractsFilterer) ParseSubmitTransaction(log types.Log) (*ContractsSubmitTransaction, error) {
-	event := new(ContractsSubmitTransaction)
-	if err := _Contracts.contract.UnpackLog(event, "SubmitTransaction", log); err != nil {
+// Solidity: event OwnerAddition(address indexed owner)
+func (_Contracts *ContractsFilterer) ParseOwnerAddition(log types.Log) (*ContractsOwnerAddition, error) {
+	event := new(ContractsOwnerAddition)
+	if err := _Contracts.contract.UnpackLog(event, "OwnerAddition", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ContractsOwnerRemovalIterator is returned from FilterOwnerRemoval and is used to iterate over the raw logs and unpacked data for OwnerRemoval events raised by the Contracts contract.
+type ContractsOwnerRemovalIterator struct {
+	Event *ContractsOwnerRemoval // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractsOwnerRemovalIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractsOwnerRemoval)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractsOwnerRemoval)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractsOwnerRemovalIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractsOwnerRemovalIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractsOwnerRemoval represents a OwnerRemoval event raised by the Contracts contract.
+type ContractsOwnerRemoval struct {
+	Owner common.Address
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnerRemoval is a free log retrieval operation binding the contract event 0x8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90.
+//
+// Solidity: event OwnerRemoval(address indexed owner)
+func (_Contracts *ContractsFilterer) FilterOwnerRemoval(opts *bind.FilterOpts, owner []common.Address) (*ContractsOwnerRemovalIterator, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _Contracts.contract.FilterLogs(opts, "OwnerRemoval", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractsOwnerRemovalIterator{contract: _Contracts.contract, event: "OwnerRemoval", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnerRemoval is a free log subscription operation binding the contract event 0x8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90.
+//
+// Solidity: event OwnerRemoval(address indexed owner)
+func (_Contracts *ContractsFilterer) WatchOwnerRemoval(opts *bind.WatchOpts, sink chan<- *ContractsOwnerRemoval, owner []common.Address) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _Contracts.contract.WatchLogs(opts, "OwnerRemoval", ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractsOwnerRemoval)
+				if err := _Contracts.contract.UnpackLog(event, "OwnerRemoval", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnerRemoval is a log parse operation binding the contract event 0x8001553a916ef2f495d26a907cc54d96ed840d7bda71e73194bf5a9df7a76b90.
+//
+// Solidity: event OwnerRemoval(address indexed owner)
+func (_Contracts *ContractsFilterer) ParseOwnerRemoval(log types.Log) (*ContractsOwnerRemoval, error) {
+	event := new(ContractsOwnerRemoval)
+	if err := _Contracts.contract.UnpackLog(event, "OwnerRemoval", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ContractsRequirementChangeIterator is returned from FilterRequirementChange and is used to iterate over the raw logs and unpacked data for RequirementChange events raised by the Contracts contract.
+type ContractsRequirementChangeIterator struct {
+	Event *ContractsRequirementChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractsRequirementChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractsRequirementChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractsRequirementChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractsRequirementChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractsRequirementChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractsRequirementChange represents a RequirementChange event raised by the Contracts contract.
+type ContractsRequirementChange struct {
+	RequiredConfirmations *big.Int
+	Raw                   types.Log // Blockchain specific contextual infos
+}
+
+// FilterRequirementChange is a free log retrieval operation binding the contract event 0xa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a.
+//
+// Solidity: event RequirementChange(uint256 requiredConfirmations)
+func (_Contracts *ContractsFilterer) FilterRequirementChange(opts *bind.FilterOpts) (*ContractsRequirementChangeIterator, error) {
+
+	logs, sub, err := _Contracts.contract.FilterLogs(opts, "RequirementChange")
+	if err != nil {
+		return nil, err
+	}
+	return &ContractsRequirementChangeIterator{contract: _Contracts.contract, event: "RequirementChange", logs: logs, sub: sub}, nil
+}
+
+// WatchRequirementChange is a free log subscription operation binding the contract event 0xa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a.
+//
+// Solidity: event RequirementChange(uint256 requiredConfirmations)
+func (_Contracts *ContractsFilterer) WatchRequirementChange(opts *bind.WatchOpts, sink chan<- *ContractsRequirementChange) (event.Subscription, error) {
+
+	logs, sub, err := _Contracts.contract.WatchLogs(opts, "RequirementChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractsRequirementChange)
+				if err := _Contracts.contract.UnpackLog(event, "RequirementChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRequirementChange is a log parse operation binding the contract event 0xa3f1ee9126a074d9326c682f561767f710e927faa811f7a99829d49dc421797a.
+//
+// Solidity: event RequirementChange(uint256 requiredConfirmations)
+func (_Contracts *ContractsFilterer) ParseRequirementChange(log types.Log) (*ContractsRequirementChange, error) {
+	event := new(ContractsRequirementChange)
+	if err := _Contracts.contract.UnpackLog(event, "RequirementChange", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ContractsRevokeConfirmationIterator is returned from FilterRevokeConfirmation and is used to iterate over the raw logs and unpacked data for RevokeConfirmation events raised by the Contracts contract.
+type ContractsRevokeConfirmationIterator struct {
+	Event *ContractsRevokeConfirmation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractsRevokeConfirmationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractsRevokeConfirmation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractsRevokeConfirmation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractsRevokeConfirmationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractsRevokeConfirmationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractsRevokeConfirmation represents a RevokeConfirmation event raised by the Contracts contract.
+type ContractsRevokeConfirmation struct {
+	Owner   common.Address
+	TxIndex *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterRevokeConfirmation is a free log retrieval operation binding the contract event 0xf0dca620e2e81f7841d07bcc105e1704fb01475b278a9d4c236e1c62945edd55.
+//
+// Solidity: event RevokeConfirmation(address indexed owner, uint256 indexed txIndex)
+func (_Contracts *ContractsFilterer) FilterRevokeConfirmation(opts *bind.FilterOpts, owner []common.Address, txIndex []*big.Int) (*ContractsRevokeConfirmationIterator, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var txIndexRule []interface{}
+	for _, txIndexItem := range txIndex {
+		txIndexRule = append(txIndexRule, txIndexItem)
+	}
+
+	logs, sub, err := _Contracts.contract.FilterLogs(opts, "RevokeConfirmation", ownerRule, txIndexRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractsRevokeConfirmationIterator{contract: _Contracts.contract, event: "RevokeConfirmation", logs: logs, sub: sub}, nil
+}
+
+// WatchRevokeConfirmation is a free log subscription operation binding the contract event 0xf0dca620e2e81f7841d07bcc105e1704fb01475b278a9d4c236e1c62945edd55.
+//
+// Solidity: event RevokeConfirmation(address indexed owner, uint256 indexed txIndex)
+func (_Contracts *ContractsFilterer) WatchRevokeConfirmation(opts *bind.WatchOpts, sink chan<- *ContractsRevokeConfirmation, owner []common.Address, txIndex []*big.Int) (event.Subscription, error) {
+
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var txIndexRule []interface{}
+	for _, txIndexItem := range txIndex {
+		txIndexRule = append(txIndexRule, txIndexItem)
+	}
+
+	logs, sub, err := _Contracts.contract.WatchLogs(opts, "RevokeConfirmation", ownerRule, txIndexRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractsRevokeConfirmation)
+				if err := _Contracts.contract.UnpackLog(event, "RevokeConfirmation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRevokeConfirmation is a log parse operation binding the contract event 0xf0dca620e2e81f7841d07bcc105e1704fb01475b278a9d4c236e1c62945edd55.
+//
+// Solidity: event RevokeConfirmation(address indexed owner, uint256 indexed txIndex)
+func (_Contracts *ContractsFilterer) ParseRevokeConfirmation(log types.Log) (*ContractsRevokeConfirmation, error) {
+	event := new(ContractsRevokeConfirmation)
+	if err := _Contracts.contract.UnpackLog(event, "RevokeConfirmation", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ContractsSubmitTransactionIterator is returned from FilterSubmitTransaction and is used to iterate over the raw logs and unpacked data for SubmitTransaction events raised by the Contracts contract.
+type ContractsSubmitTransactionIterator struct {
+	Event *ContractsSubmitTransaction // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractsSubmitTransactionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractsSubmitTransaction)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractsSubmitTransaction)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractsSubmitTransactionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractsSubmitTransactionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractsSubmitTransaction represents a SubmitTransaction event raised by the Contracts contract.
+type ContractsSubmitTransaction struct {
+	TxIndex *big.Int
+	To      common.Address
+	Value   *big.Int
+	Data    []byte
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterSubmitTransaction is a free log retrieval operation binding the contract event 0x00c2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe.
+//
+// Solidity: event SubmitTransaction(uint256 indexed txIndex, address indexed to, uint256 value, bytes data)
+func (_Contracts *ContractsFilterer) FilterSubmitTransaction(opts *bind.FilterOpts, txIndex []*big.Int, to []common.Address) (*ContractsSubmitTransactionIterator, error) {
+
+	var txIndexRule []interface{}
+	for _, txIndexItem := range txIndex {
+		txIndexRule = append(txIndexRule, txIndexItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _Contracts.contract.FilterLogs(opts, "SubmitTransaction", txIndexRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractsSubmitTransactionIterator{contract: _Contracts.contract, event: "SubmitTransaction", logs: logs, sub: sub}, nil
+}
+
+// WatchSubmitTransaction is a free log subscription operation binding the contract event 0x00c2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe.
+//
+// Solidity: event SubmitTransaction(uint256 indexed txIndex, address indexed to, uint256 value, bytes data)
+func (_Contracts *ContractsFilterer) WatchSubmitTransaction(opts *bind.WatchOpts, sink chan<- *ContractsSubmitTransaction, txIndex []*big.Int, to []common.Address) (event.Subscription, error) {
+
+	var txIndexRule []interface{}
+	for _, txIndexItem := range txIndex {
+		txIndexRule = append(txIndexRule, txIndexItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _Contracts.contract.WatchLogs(opts, "SubmitTransaction", txIndexRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractsSubmitTransaction)
+				if err := _Contracts.contract.UnpackLog(event, "SubmitTransaction", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSubmitTransaction is a log parse operation binding the contract event 0x00c2937519b07b47ea25f52e915dd99023f9b3e1aaeef041ddecbac3e9400bbe.
+//
+// Solidity: event SubmitTransaction(uint256 indexed txIndex, address indexed to, uint256 value, bytes data)
+func (_Contracts *ContractsFilterer) ParseSubmitTransaction(log types.Log) (*ContractsSubmitTransaction, error) {
+	event := new(ContractsSubmitTransaction)
+	if err := _Contracts.contract.UnpackLog(event, "SubmitTransaction", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ContractsTimelockChangeIterator is returned from FilterTimelockChange and is used to iterate over the raw logs and unpacked data for TimelockChange events raised by the Contracts contract.
+type ContractsTimelockChangeIterator struct {
+	Event *ContractsTimelockChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractsTimelockChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractsTimelockChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractsTimelockChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractsTimelockChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractsTimelockChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractsTimelockChange represents a TimelockChange event raised by the Contracts contract.
+type ContractsTimelockChange struct {
+	Delay    *big.Int
+	MinValue *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterTimelockChange is a free log retrieval operation binding the contract event 0xab9b24574eb9cb445b5a80e9f2167e93a340cb365344515be9ed471fc5fe69dc.
+//
+// Solidity: event TimelockChange(uint256 delay, uint256 minValue)
+func (_Contracts *ContractsFilterer) FilterTimelockChange(opts *bind.FilterOpts) (*ContractsTimelockChangeIterator, error) {
+
+	logs, sub, err := _Contracts.contract.FilterLogs(opts, "TimelockChange")
+	if err != nil {
+		return nil, err
+	}
+	return &ContractsTimelockChangeIterator{contract: _Contracts.contract, event: "TimelockChange", logs: logs, sub: sub}, nil
+}
+
+// WatchTimelockChange is a free log subscription operation binding the contract event 0xab9b24574eb9cb445b5a80e9f2167e93a340cb365344515be9ed471fc5fe69dc.
+//
+// Solidity: event TimelockChange(uint256 delay, uint256 minValue)
+func (_Contracts *ContractsFilterer) WatchTimelockChange(opts *bind.WatchOpts, sink chan<- *ContractsTimelockChange) (event.Subscription, error) {
+
+	logs, sub, err := _Contracts.contract.WatchLogs(opts, "TimelockChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractsTimelockChange)
+				if err := _Contracts.contract.UnpackLog(event, "TimelockChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTimelockChange is a log parse operation binding the contract event 0xab9b24574eb9cb445b5a80e9f2167e93a340cb365344515be9ed471fc5fe69dc.
+//
+// Solidity: event TimelockChange(uint256 delay, uint256 minValue)
+func (_Contracts *ContractsFilterer) ParseTimelockChange(log types.Log) (*ContractsTimelockChange, error) {
+	event := new(ContractsTimelockChange)
+	if err := _Contracts.contract.UnpackLog(event, "TimelockChange", log); err != nil {
 		return nil, err
 	}
 	event.Raw = log
@@ -0,0 +1,375 @@
+package contracts_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var ether = big.NewInt(params.Ether)
+
+// harness is a 2-of-3 wallet deployed on a simulated chain
+type harness struct {
+	t       *testing.T
+	backend *simulated.Backend
+	keys    []*ecdsa.PrivateKey
+	owners  []common.Address
+	address common.Address
+	wallet  *contracts.Contracts
+	abi     *abi.ABI
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	h := &harness{t: t}
+	alloc := types.GenesisAlloc{}
+	for i := 0; i < 4; i++ { // Three owners and an outsider
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.keys = append(h.keys, key)
+		h.owners = append(h.owners, crypto.PubkeyToAddress(key.PublicKey))
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = types.Account{Balance: new(big.Int).Mul(big.NewInt(100), ether)}
+	}
+	h.owners = h.owners[:3]
+	h.backend = simulated.NewBackend(alloc)
+	t.Cleanup(func() { h.backend.Close() })
+
+	address, _, wallet, err := contracts.DeployContracts(h.auth(0), h.backend.Client(), h.owners, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.backend.Commit()
+	h.address, h.wallet = address, wallet
+	if h.abi, err = contracts.ContractsMetaData.GetAbi(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fund the wallet through its receive function
+	h.mine(h.transfer(0, address, new(big.Int).Mul(big.NewInt(10), ether)))
+	return h
+}
+
+func (h *harness) auth(i int) *bind.TransactOpts {
+	h.t.Helper()
+	auth, err := bind.NewKeyedTransactorWithChainID(h.keys[i], big.NewInt(1337))
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return auth
+}
+
+func (h *harness) transfer(i int, to common.Address, value *big.Int) (*types.Transaction, error) {
+	h.t.Helper()
+	client := h.backend.Client()
+	from := crypto.PubkeyToAddress(h.keys[i].PublicKey)
+	nonce, err := client.PendingNonceAt(context.Background(), from)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID: big.NewInt(1337), Nonce: nonce, To: &to, Value: value, Gas: 100000,
+		GasTipCap: big.NewInt(params.GWei), GasFeeCap: big.NewInt(100 * params.GWei),
+	}), types.LatestSignerForChainID(big.NewInt(1337)), h.keys[i])
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return tx, client.SendTransaction(context.Background(), tx)
+}
+
+// mine commits a block and fails the test unless tx succeeded in it
+func (h *harness) mine(tx *types.Transaction, err error) {
+	h.t.Helper()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.backend.Commit()
+	receipt, err := h.backend.Client().TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		h.t.Fatalf("tx %s failed", tx.Hash())
+	}
+}
+
+// advance seals an empty block d later than the previous one. AdjustTime refuses while the pool still holds
+// transactions, and the pool drops mined ones in a reset that runs after Commit returns, so it is retried
+// until that reset is done.
+func (h *harness) advance(d time.Duration) {
+	h.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for err := h.backend.AdjustTime(d); err != nil; err = h.backend.AdjustTime(d) {
+		if time.Now().After(deadline) {
+			h.t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// reverts checks that a call was rejected with reason
+func (h *harness) reverts(err error, reason string) {
+	h.t.Helper()
+	if err == nil || !strings.Contains(err.Error(), reason) {
+		h.t.Fatalf("got error %v, want revert %q", err, reason)
+	}
+}
+
+// submit proposes a call from the wallet and returns its index
+func (h *harness) submit(to common.Address, value *big.Int, data []byte) *big.Int {
+	h.t.Helper()
+	count, err := h.wallet.GetTransactionCount(nil)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.mine(h.wallet.SubmitTransaction(h.auth(0), to, value, data))
+	return count
+}
+
+// govern submits a call of the wallet on itself, confirms it by owners and executes it
+func (h *harness) govern(method string, args []any, owners ...int) {
+	h.t.Helper()
+	data, err := h.abi.Pack(method, args...)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	index := h.submit(h.address, new(big.Int), data)
+	for _, i := range owners {
+		h.mine(h.wallet.ConfirmTransaction(h.auth(i), index))
+	}
+	h.mine(h.wallet.ExecuteTransaction(h.auth(owners[0]), index))
+}
+
+func (h *harness) confirmations(index *big.Int) uint64 {
+	h.t.Helper()
+	txn, err := h.wallet.Transactions(nil, index)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return txn.Confirmations.Uint64()
+}
+
+func (h *harness) balance(address common.Address) *big.Int {
+	h.t.Helper()
+	balance, err := h.backend.Client().BalanceAt(context.Background(), address, nil)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return balance
+}
+
+func TestSubmitConfirmExecute(t *testing.T) {
+	h := newHarness(t)
+	recipient := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	index := h.submit(recipient, ether, nil)
+	h.mine(h.wallet.ConfirmTransaction(h.auth(0), index))
+	_, err := h.wallet.ExecuteTransaction(h.auth(0), index)
+	h.reverts(err, "not enough confirmations")
+
+	h.mine(h.wallet.ConfirmTransaction(h.auth(1), index))
+	if got := h.confirmations(index); got != 2 {
+		t.Fatalf("got %d confirmations, want 2", got)
+	}
+	h.mine(h.wallet.ExecuteTransaction(h.auth(2), index))
+	if got := h.balance(recipient); got.Cmp(ether) != 0 {
+		t.Fatalf("recipient has %s wei, want %s", got, ether)
+	}
+	txn, err := h.wallet.GetTransaction(nil, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !txn.Executed || txn.To != recipient || txn.Value.Cmp(ether) != 0 {
+		t.Fatalf("unexpected transaction %+v", txn)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		call   func() (*types.Transaction, error)
+		reason string
+	}{
+		{"execute twice", func() (*types.Transaction, error) { return h.wallet.ExecuteTransaction(h.auth(0), index) }, "already executed"},
+		{"confirm executed", func() (*types.Transaction, error) { return h.wallet.ConfirmTransaction(h.auth(2), index) }, "already executed"},
+		{"confirm twice", func() (*types.Transaction, error) { return h.wallet.ConfirmTransaction(h.auth(0), index) }, "already confirmed"},
+		{"revoke executed", func() (*types.Transaction, error) { return h.wallet.RevokeConfirmation(h.auth(0), index) }, "already executed"},
+		{"unknown index", func() (*types.Transaction, error) { return h.wallet.ConfirmTransaction(h.auth(0), big.NewInt(99)) }, "tx does not exist"},
+		{"outsider submits", func() (*types.Transaction, error) {
+			return h.wallet.SubmitTransaction(h.auth(3), recipient, ether, nil)
+		}, "not owner"},
+		{"outsider confirms", func() (*types.Transaction, error) { return h.wallet.ConfirmTransaction(h.auth(3), index) }, "not owner"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.call()
+			h.reverts(err, tc.reason)
+		})
+	}
+}
+
+func TestFailedCallReverts(t *testing.T) {
+	h := newHarness(t)
+
+	index := h.submit(common.HexToAddress("0x1"), new(big.Int).Mul(big.NewInt(1000), ether), nil) // More than the wallet holds
+	h.mine(h.wallet.ConfirmTransaction(h.auth(0), index))
+	h.mine(h.wallet.ConfirmTransaction(h.auth(1), index))
+	_, err := h.wallet.ExecuteTransaction(h.auth(0), index)
+	h.reverts(err, "tx failed")
+}
+
+func TestRevokeConfirmation(t *testing.T) {
+	h := newHarness(t)
+
+	index := h.submit(h.owners[2], ether, nil)
+	_, err := h.wallet.RevokeConfirmation(h.auth(0), index)
+	h.reverts(err, "tx not confirmed")
+
+	h.mine(h.wallet.ConfirmTransaction(h.auth(0), index))
+	h.mine(h.wallet.ConfirmTransaction(h.auth(1), index))
+	h.mine(h.wallet.RevokeConfirmation(h.auth(1), index))
+	if got := h.confirmations(index); got != 1 {
+		t.Fatalf("got %d confirmations after revoking, want 1", got)
+	}
+	if confirmed, err := h.wallet.IsConfirmed(nil, index, h.owners[1]); err != nil || confirmed {
+		t.Fatalf("revoked confirmation still counts (%v)", err)
+	}
+	_, err = h.wallet.ExecuteTransaction(h.auth(0), index)
+	h.reverts(err, "not enough confirmations")
+
+	h.mine(h.wallet.ConfirmTransaction(h.auth(2), index))
+	h.mine(h.wallet.ExecuteTransaction(h.auth(0), index))
+}
+
+func TestOwnerChanges(t *testing.T) {
+	h := newHarness(t)
+	newOwner := crypto.PubkeyToAddress(h.keys[3].PublicKey)
+
+	for _, tc := range []struct {
+		name string
+		call func() (*types.Transaction, error)
+	}{
+		{"addOwner", func() (*types.Transaction, error) { return h.wallet.AddOwner(h.auth(0), newOwner) }},
+		{"removeOwner", func() (*types.Transaction, error) { return h.wallet.RemoveOwner(h.auth(0), h.owners[2]) }},
+		{"replaceOwner", func() (*types.Transaction, error) { return h.wallet.ReplaceOwner(h.auth(0), h.owners[2], newOwner) }},
+		{"changeRequirement", func() (*types.Transaction, error) { return h.wallet.ChangeRequirement(h.auth(0), big.NewInt(1)) }},
+		{"changeTimelock", func() (*types.Transaction, error) {
+			return h.wallet.ChangeTimelock(h.auth(0), big.NewInt(60), new(big.Int))
+		}},
+	} {
+		t.Run(tc.name+" needs the wallet", func(t *testing.T) {
+			_, err := tc.call()
+			h.reverts(err, "only wallet")
+		})
+	}
+
+	// A pending proposal confirmed by the owner about to be removed
+	pending := h.submit(h.owners[0], ether, nil)
+	h.mine(h.wallet.ConfirmTransaction(h.auth(2), pending))
+
+	h.govern("removeOwner", []any{h.owners[2]}, 0, 1)
+	if isOwner, _ := h.wallet.IsOwner(nil, h.owners[2]); isOwner {
+		t.Fatal("removed owner is still an owner")
+	}
+	if got := h.confirmations(pending); got != 0 {
+		t.Fatalf("removed owner's confirmation still counts: %d confirmations", got)
+	}
+
+	// Coming back as an owner does not revive the old confirmation
+	h.govern("addOwner", []any{h.owners[2]}, 0, 1)
+	if confirmed, _ := h.wallet.IsConfirmed(nil, pending, h.owners[2]); confirmed {
+		t.Fatal("confirmation from an earlier term counts again")
+	}
+	h.mine(h.wallet.ConfirmTransaction(h.auth(2), pending))
+
+	h.govern("replaceOwner", []any{h.owners[2], newOwner}, 0, 1)
+	if got := h.confirmations(pending); got != 0 {
+		t.Fatalf("replaced owner's confirmation still counts: %d confirmations", got)
+	}
+	h.mine(h.wallet.ConfirmTransaction(h.auth(3), pending))
+
+	h.govern("changeRequirement", []any{big.NewInt(1)}, 0, 1)
+	h.mine(h.wallet.ExecuteTransaction(h.auth(3), pending)) // One confirmation is enough now
+
+	data, err := h.abi.Pack("changeRequirement", big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := h.submit(h.address, new(big.Int), data)
+	h.mine(h.wallet.ConfirmTransaction(h.auth(0), index))
+	_, err = h.wallet.ExecuteTransaction(h.auth(0), index)
+	h.reverts(err, "tx failed") // More confirmations than owners
+}
+
+func TestTimelock(t *testing.T) {
+	h := newHarness(t)
+	const delay = 3600
+	h.govern("changeTimelock", []any{big.NewInt(delay), ether}, 0, 1)
+
+	large := h.submit(h.owners[2], ether, nil)
+	small := h.submit(h.owners[2], big.NewInt(1), nil)
+	call := h.submit(h.owners[2], new(big.Int), []byte{0xa9, 0x05, 0x9c, 0xbb}) // Calldata moving no ETH, like a token transfer
+	for _, index := range []*big.Int{large, small, call} {
+		h.mine(h.wallet.ConfirmTransaction(h.auth(0), index))
+		h.mine(h.wallet.ConfirmTransaction(h.auth(1), index))
+	}
+
+	h.mine(h.wallet.ExecuteTransaction(h.auth(0), small)) // Below timelockMinValue
+	for _, index := range []*big.Int{large, call} {
+		_, err := h.wallet.ExecuteTransaction(h.auth(0), index)
+		h.reverts(err, "timelocked")
+	}
+
+	reached, err := h.wallet.ConfirmedAt(nil, large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, err := h.wallet.ExecutableAt(nil, large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reached.Sign() == 0 || at.Uint64() != reached.Uint64()+delay {
+		t.Fatalf("executableAt %s, want confirmedAt %s + %d", at, reached, delay)
+	}
+
+	// Dropping below the threshold resets the delay
+	h.mine(h.wallet.RevokeConfirmation(h.auth(1), large))
+	if at, _ := h.wallet.ExecutableAt(nil, large); at.Sign() != 0 {
+		t.Fatalf("executableAt %s below the threshold, want 0", at)
+	}
+	h.advance(delay * time.Second)
+	h.mine(h.wallet.ExecuteTransaction(h.auth(0), call))
+	h.mine(h.wallet.ConfirmTransaction(h.auth(1), large))
+	_, err = h.wallet.ExecuteTransaction(h.auth(0), large)
+	h.reverts(err, "timelocked")
+
+	h.advance(delay * time.Second)
+	h.mine(h.wallet.ExecuteTransaction(h.auth(0), large))
+
+	// A threshold change restarts the delay of proposals that already reached the threshold
+	pending := h.submit(h.owners[2], ether, nil)
+	h.mine(h.wallet.ConfirmTransaction(h.auth(0), pending))
+	h.mine(h.wallet.ConfirmTransaction(h.auth(1), pending))
+	h.advance(delay * time.Second)
+	data, err := h.abi.Pack("changeRequirement", big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	change := h.submit(h.address, new(big.Int), data)
+	h.mine(h.wallet.ConfirmTransaction(h.auth(0), change))
+	h.mine(h.wallet.ConfirmTransaction(h.auth(1), change))
+	h.advance(delay * time.Second)
+	h.mine(h.wallet.ExecuteTransaction(h.auth(0), change))
+	_, err = h.wallet.ExecuteTransaction(h.auth(0), pending)
+	h.reverts(err, "timelocked")
+}
@@ -1,77 +1,144 @@
 package main
 
 import (
-	"crypto/ecdsa"
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain"
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/signer"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-)
-
-const (
-	rpcURL        = "https://sepolia.infura.io/v3/0fb5b52beb4d454c965b32ef7dd465a9"    // Ethereum node RPC endpoint
-	privateKeyHex = "4b1321d42a47cc5c083a1e6e29e1c4049d43b87de123590d8df55f5fc0ec5ea2" // Private key of the sender
-	contractAddr  = "0x842e6C440F574bfc82c27Fa43226B1dC6883A344"                       // Already deployed multisig wallet contract address
+	"golang.org/x/term"
 )
 
 func main() {
-	// Connect to the Ethereum node
-	client, err := ethclient.Dial(rpcURL)
-	if err != nil {
-		log.Fatal("Failed to connect to Ethereum node:", err)
-	}
-	defer client.Close()
+	configPath := flag.String("config", config.Path(), "path to the JSON config file")
+	action := flag.String("action", "submit", "deploy, transfer, submit, confirm, revoke or execute")
+	contractAddr := flag.String("contract", "", "address of the already deployed multisig wallet contract")
+	toAddr := flag.String("to", "", "recipient of the submitted transaction or transfer")
+	valueWei := flag.String("value", "100000000000000000", "submit: value of the submitted transaction in wei (default 0.1 ETH)")
+	callData := flag.String("data", "", "submit: hex calldata of the submitted transaction (default none, a plain ETH transfer)")
+	amount := flag.String("amount", "", "transfer: amount in ETH")
+	owners := flag.String("owners", "", "deploy: comma separated owner addresses")
+	required := flag.Uint("required", 1, "deploy: required confirmations")
+	index := flag.Uint64("index", 0, "confirm/revoke/execute: proposal index")
+	signerRef := flag.String("signer", "", "signer name or keystore account acting (default signer if empty)")
+	feeStrategy := flag.String("fee", "standard", "fee strategy: slow, standard or fast")
+	maxFee := flag.String("max-fee", "", "cap on the max fee per gas in wei (gas price on legacy chains)")
+	maxTip := flag.String("max-tip", "", "cap on the priority fee per gas in wei")
+	gasLimit := flag.Uint64("gas", 0, "gas limit (default: estimate plus the configured margin)")
+	flag.Parse()
 
-	// Loading senders  private key
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	// Load the network and signers from the config file / MULTISIG_* environment
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatal("Invalid private key:", err)
+		log.Fatal("Invalid configuration: ", err)
 	}
+	config.Set(cfg)
 
-	// Retriving ethererum addrss from public key
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("Error casting public key")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	fmt.Println("Using address:", fromAddress.Hex())
-
-	// Read and parse contract ABI from file
-	abiFile, err := os.ReadFile("interact/contract_abi.json")
+	// Resolving the sender's signer, the key itself stays in the keystore or signing service
+	keys := wallet.NewStore(cfg.KeystoreDir())
+	signers, err := signer.FromConfig(cfg, keys)
 	if err != nil {
-		log.Fatal("Unable to read ABI file: ", err)
+		log.Fatal("Invalid signer configuration:", err)
 	}
-	contractABI, err := abi.JSON(strings.NewReader(string(abiFile)))
+	sender, err := signers.Resolve(*signerRef)
 	if err != nil {
-		log.Fatal("Invalid ABI:  ", err)
+		log.Fatal(err)
 	}
-	//Bind the contract at the given address
-	contractAddress := common.HexToAddress(contractAddr)
-	instance := bind.NewBoundContract(contractAddress, contractABI, client, client, client)
+	if keys.Has(sender.Address()) {
+		if err := keys.Unlock(sender.Address(), readPassphrase(), 0); err != nil {
+			log.Fatal("Unlock failed:", err)
+		}
+	}
+	fmt.Println("Using address:", sender.Address().Hex())
 
-	// Create authorized transactor (for signing)
-	chainID := big.NewInt(11155111) // Sepolia Chain ID
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	opts := blockchain.TxOptions{FeeStrategy: blockchain.FeeStrategy(*feeStrategy), MaxFeePerGas: *maxFee, MaxPriorityFeePerGas: *maxTip, GasLimit: *gasLimit}
+	var res blockchain.TxResult
+	switch *action {
+	case "deploy":
+		var ownerList []common.Address
+		for _, o := range strings.Split(*owners, ",") {
+			ownerList = append(ownerList, common.HexToAddress(strings.TrimSpace(o)))
+		}
+		job, _, err := blockchain.DeployMultisigWallet(sender, ownerList, uint8(*required), 0, opts)
+		if err != nil {
+			log.Fatal("Deployment failed:", err)
+		}
+		fmt.Println("Tx hash:", job.ID)
+		fmt.Println("Waiting for", job.Depth, "confirmations...")
+		job, err = blockchain.DeploymentJobs().Wait(context.Background(), job.ID)
+		if err != nil {
+			log.Fatal("Deployment failed:", err)
+		}
+		if job.Status != blockchain.DeployVerified {
+			log.Fatal("Deployment ", job.Status, ": ", job.Error)
+		}
+		fmt.Println("Multisig address:", job.ContractAddress)
+		return
+	case "transfer":
+		res, err = blockchain.SendTransaction(blockchain.TransferRequest{ToAddress: *toAddr, Amount: *amount, TxOptions: opts}, sender)
+	case "confirm":
+		res, err = blockchain.ConfirmMultisigTransaction(blockchain.MultisigTxRequest{ContractAddress: *contractAddr, TxIndex: *index, TxOptions: opts}, sender)
+	case "revoke":
+		res, err = blockchain.RevokeMultisigConfirmation(blockchain.MultisigTxRequest{ContractAddress: *contractAddr, TxIndex: *index, TxOptions: opts}, sender)
+	case "execute":
+		res, err = blockchain.ExecuteMultisigTransaction(blockchain.MultisigTxRequest{ContractAddress: *contractAddr, TxIndex: *index, TxOptions: opts}, sender)
+	case "submit":
+		res, err = submit(sender, *contractAddr, *toAddr, *valueWei, *callData, opts)
+	default:
+		log.Fatal("unknown -action ", *action)
+	}
 	if err != nil {
-		log.Fatal("Failed to create transactor:", err)
+		log.Fatal(*action, " failed: ", err)
 	}
+	fmt.Println("Tx hash:", res.TxHash)
+	if network, err := cfg.Active(); err == nil && network.TxURL(res.TxHash) != "" {
+		fmt.Println("Explorer:", network.TxURL(res.TxHash))
+	}
+}
 
-	// Example: Call submitTransaction(to, value, data) Prepare transaction parameters
-	to := common.HexToAddress("0xA71125e0210d353Ef642eDdA95419e240f7b3C2a")
-	value := big.NewInt(100000000000000000) // 0.1 ETH
-	data := []byte{}                        // empty calldata for ETH transfer
+// submit proposes to -> value (wei) with calldata through the service's submit path, so the nonce
+// manager hands out the nonce and the transaction is tracked like any other
+func submit(sender signer.Signer, contractAddr, toAddr, valueWei, callData string, opts blockchain.TxOptions) (blockchain.TxResult, error) {
+	if contractAddr == "" || toAddr == "" {
+		return blockchain.TxResult{}, fmt.Errorf("-contract and -to are required")
+	}
+	value, ok := new(big.Int).SetString(valueWei, 10)
+	if !ok || value.Sign() < 0 {
+		return blockchain.TxResult{}, fmt.Errorf("invalid value: %s", valueWei)
+	}
+	ether := new(big.Rat).SetFrac(value, big.NewInt(1e18)).FloatString(18) // The request takes ETH
+	return blockchain.SubmitMultisigTransaction(blockchain.SubmitMultisigTxRequest{
+		ContractAddress: contractAddr,
+		To:              toAddr,
+		Value:           ether,
+		CallSpec:        blockchain.CallSpec{Data: callData}, // Empty for a plain ETH transfer
+		TxOptions:       opts,
+	}, sender)
+}
 
-	tx, err := instance.Transact(auth, "submitTransaction", to, value, data) // submitting the transaction using a contratc.
+// readPassphrase returns the keystore passphrase from WALLETCTL_PASSPHRASE, or asks for it on the terminal
+// without echoing it, so it never shows up in the shell history or the process list
+func readPassphrase() string {
+	if passphrase, ok := os.LookupEnv("WALLETCTL_PASSPHRASE"); ok {
+		return passphrase
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		log.Fatal("standard input is not a terminal: set WALLETCTL_PASSPHRASE to give the keystore passphrase")
+	}
+	fmt.Fprint(os.Stderr, "Passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
 	if err != nil {
-		log.Fatal("submitTransaction failed:", err)
+		log.Fatal(err)
 	}
-	fmt.Println("Submitted transaction! Tx hash:", tx.Hash().Hex()) //Transaction hash.
+	return string(passphrase)
 }
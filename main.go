@@ -2,18 +2,31 @@
 package main
 
 import (
+	"flag"     // For the -config command line flag
 	"log"      // For logging server startup and fatal errors
 	"net/http" // For starting the HTTP server
 
-	"github.com/akarkareddy/ethereum-multisig-wallet/api" // Import the custom API package where handlers and routes are defined
+	"github.com/akarkareddy/ethereum-multisig-wallet/api"    // Import the custom API package where handlers and routes are defined
+	"github.com/akarkareddy/ethereum-multisig-wallet/config" // Network and service configuration (file + environment)
 
 	"github.com/gorilla/mux" // Gorilla Mux is a powerful router for HTTP request routing(Rest Api)
 )
 
 func main() {
-	router := mux.NewRouter() // Create a new router using Gorilla Mux to handle different HTTP routes
-	api.SetupRoutes(router)   // Call a helper function from the API package to set or register all end points
+	configPath := flag.String("config", config.Path(), "path to the JSON config file")
+	flag.Parse()
 
-	log.Println("Server started on http://localhost:8080") // Log to console that the server has started and is listening on port 8080
-	log.Fatal(http.ListenAndServe(":8080", router))        // If the server crashes or can't start, log.Fatal will print the error and exit the program
+	cfg, err := config.Load(*configPath) // Read the config file and apply MULTISIG_* environment overrides
+	if err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	config.Set(cfg)
+
+	router := mux.NewRouter()                       // Create a new router using Gorilla Mux to handle different HTTP routes
+	if err := api.SetupRoutes(router); err != nil { // Call a helper function from the API package to set or register all end points
+		log.Fatal("API setup failed: ", err)
+	}
+
+	log.Printf("Server started on %s (network %s)", cfg.ListenAddr, cfg.Network) // Log the listen address and the active network
+	log.Fatal(http.ListenAndServe(cfg.ListenAddr, router))                       // If the server crashes or can't start, log.Fatal will print the error and exit the program
 }
@@ -0,0 +1,137 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes" // Request types of the Clef external API
+)
+
+// Clef content types accepted by account_signData
+const (
+	ClefTextPlain     = "text/plain"     // EIP-191 personal message (0x45)
+	ClefDataValidator = "data/validator" // EIP-191 intended validator (0x00)
+)
+
+// ErrRawHashUnsupported is returned by ClefSigner.SignHash: Clef only signs structured or prefixed
+// data so that a key holder can always see what they approve. Use SignData instead.
+var ErrRawHashUnsupported = errors.New("clef does not sign raw hashes, use SignData")
+
+// clefTimeout bounds a signing call; Clef may wait for a human to approve the request
+const clefTimeout = 5 * time.Minute
+
+// ClefSigner signs through an external signer speaking the Clef JSON-RPC API
+// (account_list, account_signTransaction, account_signData), over HTTP or IPC.
+// Keys and approval rules stay in that process.
+type ClefSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// clefSignTxResult is the account_signTransaction response
+type clefSignTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// NewClefSigner signs as address through the Clef endpoint (http(s) URL or IPC path).
+// The connection is lazy, so the signer may start after this service.
+func NewClefSigner(endpoint string, address common.Address) (*ClefSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("clef: %w", err)
+	}
+	return &ClefSigner{client: client, address: address}, nil
+}
+
+func (s *ClefSigner) Address() common.Address {
+	return s.address
+}
+
+// Accounts returns the accounts the external signer manages (account_list)
+func (s *ClefSigner) Accounts() ([]common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), clefTimeout)
+	defer cancel()
+
+	var list []common.Address
+	if err := s.client.CallContext(ctx, &list, "account_list"); err != nil {
+		return nil, fmt.Errorf("clef account_list: %w", err)
+	}
+	return list, nil
+}
+
+// SignTx asks Clef to sign tx (account_signTransaction) and checks the result matches the request
+func (s *ClefSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		return nil, fmt.Errorf("clef: unsupported tx type %d", tx.Type())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clefTimeout)
+	defer cancel()
+
+	var res clefSignTxResult
+	if err := s.client.CallContext(ctx, &res, "account_signTransaction", &args); err != nil { // Pointer so the mixed case addresses marshal as hex
+		return nil, fmt.Errorf("clef account_signTransaction: %w", err)
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(res.Raw); err != nil {
+		return nil, fmt.Errorf("clef returned an invalid transaction: %w", err)
+	}
+	if err := checkSignedTx(tx, signed, s.address, chainID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// SignHash is not available through Clef, see ErrRawHashUnsupported
+func (s *ClefSigner) SignHash(hash []byte) ([]byte, error) {
+	return nil, ErrRawHashUnsupported
+}
+
+// SignData asks Clef to sign data of the given content type (account_signData).
+// The signature is returned with V normalized to 0/1 like the other signers.
+func (s *ClefSigner) SignData(contentType string, data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), clefTimeout)
+	defer cancel()
+
+	var sig hexutil.Bytes
+	address := common.NewMixedcaseAddress(s.address)
+	if err := s.client.CallContext(ctx, &sig, "account_signData", contentType, &address, hexutil.Encode(data)); err != nil {
+		return nil, fmt.Errorf("clef account_signData: %w", err)
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("clef returned a %d byte signature", len(sig))
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27 // Clef returns the Ethereum style 27/28 recovery id
+	}
+	return sig, nil
+}
@@ -0,0 +1,140 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+)
+
+// Info describes a registered signer without exposing anything secret
+type Info struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Address string `json:"address"`
+}
+
+// Registry resolves the signer reference carried by a request: a configured signer name,
+// the address of a configured signer, or the address of a keystore account.
+type Registry struct {
+	mu       sync.RWMutex
+	named    map[string]Signer
+	types    map[string]string
+	keys     *wallet.Store
+	fallback string // Name used when a request names no signer
+}
+
+// NewRegistry creates an empty registry that falls back to accounts in keys
+func NewRegistry(keys *wallet.Store) *Registry {
+	return &Registry{named: map[string]Signer{}, types: map[string]string{}, keys: keys}
+}
+
+// FromConfig builds the registry from the signers section of cfg. A legacy privateKey setting
+// is registered as the key signer "default".
+func FromConfig(cfg *config.Config, keys *wallet.Store) (*Registry, error) {
+	r := NewRegistry(keys)
+	if cfg.PrivateKey != "" {
+		s, err := KeySignerFromHex(cfg.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("privateKey: %w", err)
+		}
+		r.Register("default", "key", s)
+	}
+
+	for name, sc := range cfg.Signers {
+		s, err := New(sc, keys)
+		if err != nil {
+			return nil, fmt.Errorf("signer %q: %w", name, err)
+		}
+		r.Register(name, sc.Type, s)
+	}
+
+	r.fallback = cfg.DefaultSigner
+	if r.fallback == "" && cfg.PrivateKey != "" {
+		r.fallback = "default"
+	}
+	return r, nil
+}
+
+// New creates the signer described by sc
+func New(sc config.SignerConfig, keys *wallet.Store) (Signer, error) {
+	switch sc.Type {
+	case "key":
+		hexKey := os.Getenv(sc.KeyEnv)
+		if sc.KeyEnv == "" || hexKey == "" {
+			return nil, fmt.Errorf("key signer needs keyEnv naming a set environment variable")
+		}
+		return KeySignerFromHex(hexKey)
+	case "keystore":
+		if !common.IsHexAddress(sc.Address) {
+			return nil, fmt.Errorf("keystore signer needs an address")
+		}
+		return NewKeystoreSigner(keys, common.HexToAddress(sc.Address)), nil
+	case "remote":
+		if sc.URL == "" || !common.IsHexAddress(sc.Address) {
+			return nil, fmt.Errorf("remote signer needs url and address")
+		}
+		return NewRemoteSigner(sc.URL, common.HexToAddress(sc.Address)), nil
+	case "clef":
+		if sc.URL == "" || !common.IsHexAddress(sc.Address) {
+			return nil, fmt.Errorf("clef signer needs url (http endpoint or IPC path) and address")
+		}
+		return NewClefSigner(sc.URL, common.HexToAddress(sc.Address))
+	default:
+		return nil, fmt.Errorf("unknown signer type %q", sc.Type)
+	}
+}
+
+// Register adds s under name, replacing any previous signer with that name
+func (r *Registry) Register(name, kind string, s Signer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.named[name] = s
+	r.types[name] = kind
+}
+
+// Resolve returns the signer for ref. An empty ref means the configured default signer.
+func (r *Registry) Resolve(ref string) (Signer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if ref == "" {
+		ref = r.fallback
+		if ref == "" {
+			return nil, fmt.Errorf("%w: no signer given and no default signer configured", ErrUnknownSigner)
+		}
+	}
+	if s, ok := r.named[ref]; ok {
+		return s, nil
+	}
+	if common.IsHexAddress(ref) {
+		address := common.HexToAddress(ref)
+		for _, s := range r.named {
+			if s.Address() == address {
+				return s, nil
+			}
+		}
+		if r.keys != nil && r.keys.Has(address) {
+			return NewKeystoreSigner(r.keys, address), nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSigner, ref)
+}
+
+// List returns the named signers sorted by name
+func (r *Registry) List() []Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := []Info{}
+	for name, s := range r.named {
+		list = append(list, Info{Name: name, Type: r.types[name], Address: s.Address().Hex()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,182 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RemoteSigner asks a signing service over HTTP. The protocol is two JSON endpoints:
+//
+//	POST {url}/sign/tx   {"address", "chainId", "tx": <0x RLP of the unsigned tx>} -> {"tx": <0x RLP of the signed tx>}
+//	POST {url}/sign/hash {"address", "hash"}                                          -> {"signature": <0x 65 bytes>}
+//
+// Errors are any non-2xx status with the reason in the body. NewRemoteHandler serves the same protocol.
+type RemoteSigner struct {
+	url     string
+	address common.Address
+	client  *http.Client
+}
+
+type remoteTxRequest struct {
+	Address common.Address `json:"address"`
+	ChainID *hexutil.Big   `json:"chainId"`
+	Tx      hexutil.Bytes  `json:"tx"`
+}
+
+type remoteTxResponse struct {
+	Tx hexutil.Bytes `json:"tx"`
+}
+
+type remoteHashRequest struct {
+	Address common.Address `json:"address"`
+	Hash    hexutil.Bytes  `json:"hash"`
+}
+
+type remoteHashResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// NewRemoteSigner signs as address through the service at url
+func NewRemoteSigner(url string, address common.Address) *RemoteSigner {
+	return &RemoteSigner{
+		url:     strings.TrimRight(url, "/"),
+		address: address,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var resp remoteTxResponse
+	if err := s.post("/sign/tx", remoteTxRequest{Address: s.address, ChainID: (*hexutil.Big)(chainID), Tx: raw}, &resp); err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(resp.Tx); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+	if err := checkSignedTx(tx, signed, s.address, chainID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+func (s *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	var resp remoteHashResponse
+	if err := s.post("/sign/hash", remoteHashRequest{Address: s.address, Hash: hash}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Signature) != 65 {
+		return nil, fmt.Errorf("remote signer returned a %d byte signature", len(resp.Signature))
+	}
+	return resp.Signature, nil
+}
+
+func (s *RemoteSigner) post(path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("remote signer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var msg bytes.Buffer
+		msg.ReadFrom(resp.Body)
+		return fmt.Errorf("remote signer: %s: %s", resp.Status, strings.TrimSpace(msg.String()))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// checkSignedTx makes sure an external signer signed what was asked, as the right account.
+// A compromised or buggy signer must not be able to swap the recipient, value or data.
+func checkSignedTx(unsigned, signed *types.Transaction, address common.Address, chainID *big.Int) error {
+	if unsigned.Type() != signed.Type() || unsigned.Nonce() != signed.Nonce() ||
+		unsigned.Gas() != signed.Gas() || unsigned.Value().Cmp(signed.Value()) != 0 ||
+		!bytes.Equal(unsigned.Data(), signed.Data()) ||
+		unsigned.GasPrice().Cmp(signed.GasPrice()) != 0 || unsigned.GasTipCap().Cmp(signed.GasTipCap()) != 0 ||
+		(unsigned.To() == nil) != (signed.To() == nil) || (unsigned.To() != nil && *unsigned.To() != *signed.To()) {
+		return fmt.Errorf("signer returned a transaction different from the one requested")
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("signer returned an invalid signature: %w", err)
+	}
+	if from != address {
+		return fmt.Errorf("signer signed as %s instead of %s", from.Hex(), address.Hex())
+	}
+	return nil
+}
+
+// NewRemoteHandler serves the RemoteSigner protocol for the given signers, e.g. to run keys in a separate process
+func NewRemoteHandler(signers ...Signer) http.Handler {
+	byAddress := map[common.Address]Signer{}
+	for _, s := range signers {
+		byAddress[s.Address()] = s
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sign/tx", func(w http.ResponseWriter, r *http.Request) {
+		var req remoteTxRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ChainID == nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		s, ok := byAddress[req.Address]
+		if !ok {
+			http.Error(w, ErrUnknownSigner.Error(), http.StatusNotFound)
+			return
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(req.Tx); err != nil {
+			http.Error(w, "Invalid transaction: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		signed, err := s.SignTx(tx, req.ChainID.ToInt())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		raw, _ := signed.MarshalBinary()
+		json.NewEncoder(w).Encode(remoteTxResponse{Tx: raw})
+	})
+	mux.HandleFunc("POST /sign/hash", func(w http.ResponseWriter, r *http.Request) {
+		var req remoteHashRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Hash) != 32 {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		s, ok := byAddress[req.Address]
+		if !ok {
+			http.Error(w, ErrUnknownSigner.Error(), http.StatusNotFound)
+			return
+		}
+		sig, err := s.SignHash(req.Hash)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(remoteHashResponse{Signature: sig})
+	})
+	return mux
+}
@@ -0,0 +1,98 @@
+// Package signer hides where a key lives from the code that builds transactions.
+// Requests name a signer (or a keystore account) and never carry key material themselves.
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+)
+
+var ErrUnknownSigner = errors.New("unknown signer")
+
+// Signer signs on behalf of one Ethereum account
+type Signer interface {
+	Address() common.Address                                                    // Account the signatures belong to
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) // Returns tx with its signature attached
+	SignHash(hash []byte) ([]byte, error)                                       // Signs a 32 byte hash, [R || S || V] with V 0 or 1
+}
+
+// TransactOpts adapts s to the go-ethereum contract bindings
+func TransactOpts(s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(tx, chainID)
+		},
+		Context: context.Background(),
+	}
+}
+
+// KeySigner signs with a private key held in memory. Meant for devnets and tools; services should
+// prefer keystore or remote signers.
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner wraps an in-memory private key
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// KeySignerFromHex parses a hex private key, with or without 0x
+func KeySignerFromHex(privateKeyHex string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewKeySigner(key), nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewLondonSigner(chainID), s.key) // Legacy, access list and dynamic fee transactions
+}
+
+func (s *KeySigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+// KeystoreSigner signs with a keystore account. The account must be unlocked, otherwise signing
+// fails with wallet.ErrLocked.
+type KeystoreSigner struct {
+	keys    *wallet.Store
+	address common.Address
+}
+
+// NewKeystoreSigner signs as address using the keys in store
+func NewKeystoreSigner(store *wallet.Store, address common.Address) *KeystoreSigner {
+	return &KeystoreSigner{keys: store, address: address}
+}
+
+func (s *KeystoreSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.keys.SignTx(s.address, tx, chainID)
+}
+
+func (s *KeystoreSigner) SignHash(hash []byte) ([]byte, error) {
+	return s.keys.SignHash(s.address, hash)
+}
@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SplitKey decrypts the key of address and splits it into total Shamir shares with the given threshold
+func (s *Store) SplitKey(address common.Address, passphrase string, threshold, total int) ([]Share, error) {
+	account, err := s.ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, err
+	}
+	keyJSON, err := os.ReadFile(account.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return SplitKey(key.PrivateKey, threshold, total)
+}
+
+// RecoverKey combines shares into the key of expected and stores it encrypted with passphrase.
+// Recovery fails unless the key controls expected (and the address recorded in the shares).
+func (s *Store) RecoverKey(shares []Share, expected common.Address, passphrase string) (common.Address, error) {
+	if passphrase == "" {
+		return common.Address{}, ErrEmptyPassphrase
+	}
+	key, err := CombineKey(shares)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if shares[0].Address != expected {
+		return common.Address{}, ErrRecoveredWrongAddr
+	}
+	account, err := s.ks.ImportECDSA(key, passphrase)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// SplitMnemonic splits the mnemonic of HD wallet id into total Shamir shares with the given threshold.
+// The BIP-39 passphrase is not part of the shares; it is needed again for recovery.
+func (s *HDStore) SplitMnemonic(id, passphrase string, threshold, total int) ([]Share, error) {
+	w, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.decrypt(w, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return SplitMnemonic(secret.Mnemonic, secret.Passphrase, threshold, total)
+}
+
+// RecoverShares combines shares into a mnemonic whose account 0 is expected and restores the
+// HD wallet from it, like Recover
+func (s *HDStore) RecoverShares(shares []Share, expected common.Address, mnemonicPassphrase, passphrase string, count uint32) (*HDWallet, error) {
+	mnemonic, err := CombineMnemonic(shares, mnemonicPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	if shares[0].Address != expected {
+		return nil, ErrRecoveredWrongAddr
+	}
+	return s.Recover(mnemonic, mnemonicPassphrase, passphrase, count)
+}
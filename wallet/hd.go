@@ -0,0 +1,143 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts" // BIP-32 derivation path parsing
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39" // BIP-39 word list, mnemonic checksum and seed stretching
+)
+
+// BasePath is the BIP-44 path of Ethereum accounts; account n lives at BasePath/n
+const BasePath = "m/44'/60'/0'/0"
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// NewMnemonic returns a new random BIP-39 mnemonic. bits is the entropy size: 128 (12 words) to 256 (24 words).
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the words against the English list and the embedded checksum
+func ValidateMnemonic(mnemonic string) error {
+	if !bip39.IsMnemonicValid(normalizeMnemonic(mnemonic)) {
+		return ErrInvalidMnemonic
+	}
+	return nil
+}
+
+// MnemonicToSeed validates mnemonic and stretches it into the 64 byte BIP-39 seed.
+// passphrase is the optional BIP-39 passphrase ("25th word"), not the keystore passphrase.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return bip39.NewSeed(normalizeMnemonic(mnemonic), passphrase), nil
+}
+
+// AccountPath returns the BIP-44 path of account index, m/44'/60'/0'/0/index
+func AccountPath(index uint32) string {
+	return fmt.Sprintf("%s/%d", BasePath, index)
+}
+
+// DeriveAccount derives the private key of account index from a BIP-39 seed
+func DeriveAccount(seed []byte, index uint32) (*ecdsa.PrivateKey, error) {
+	path, err := accounts.ParseDerivationPath(AccountPath(index))
+	if err != nil {
+		return nil, err
+	}
+	return DeriveKey(seed, path)
+}
+
+// DeriveAddresses returns the addresses of accounts from..from+n-1 without storing any key
+func DeriveAddresses(mnemonic, passphrase string, from, n uint32) ([]common.Address, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []common.Address
+	for i := from; i < from+n; i++ {
+		key, err := DeriveAccount(seed, i)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return addresses, nil
+}
+
+// DeriveKey walks a BIP-32 derivation path from the master key of seed
+func DeriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	if err := checkScalar(key); err != nil {
+		return nil, err
+	}
+
+	for _, index := range path {
+		var err error
+		if key, chainCode, err = deriveChild(key, chainCode, index); err != nil {
+			return nil, fmt.Errorf("derivation of %s failed: %w", path, err)
+		}
+	}
+	return crypto.ToECDSA(key)
+}
+
+// deriveChild implements BIP-32 private parent key -> private child key (CKDpriv)
+func deriveChild(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	var data []byte
+	if index >= 0x80000000 { // Hardened child: 0x00 || parent key
+		data = append([]byte{0}, key...)
+	} else { // Normal child: compressed parent public key
+		parent, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&parent.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	if err := checkScalar(sum[:32]); err != nil {
+		return nil, nil, err
+	}
+
+	n := crypto.S256().Params().N
+	child := new(big.Int).SetBytes(sum[:32])
+	child.Add(child, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, errors.New("derived key is zero")
+	}
+	return child.FillBytes(make([]byte, 32)), sum[32:], nil
+}
+
+// checkScalar rejects the (astronomically unlikely) values BIP-32 says to skip: zero or >= curve order
+func checkScalar(b []byte) error {
+	k := new(big.Int).SetBytes(b)
+	if k.Sign() == 0 || k.Cmp(crypto.S256().Params().N) >= 0 {
+		return errors.New("invalid derived key")
+	}
+	return nil
+}
+
+// normalizeMnemonic collapses whitespace and case so pasted backups compare equal
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
@@ -0,0 +1,135 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// Private keys of the BIP-32 test vectors 1 and 3
+func TestDeriveKeyBIP32Vectors(t *testing.T) {
+	const (
+		seed1 = "000102030405060708090a0b0c0d0e0f"
+		seed3 = "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be"
+	)
+	for _, tc := range []struct {
+		seed, path, key string
+	}{
+		{seed1, "m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{seed1, "m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{seed1, "m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{seed1, "m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{seed1, "m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{seed1, "m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+		{seed3, "m", "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32"}, // Leading zero kept
+		{seed3, "m/0'", "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			seed, err := hex.DecodeString(tc.seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var path accounts.DerivationPath
+			if tc.path != "m" {
+				if path, err = accounts.ParseDerivationPath(tc.path); err != nil {
+					t.Fatal(err)
+				}
+			}
+			key, err := DeriveKey(seed, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(crypto.FromECDSA(key)); got != tc.key {
+				t.Fatalf("got key %s, want %s", got, tc.key)
+			}
+		})
+	}
+}
+
+func TestDeriveChildChainCode(t *testing.T) {
+	// BIP-32 test vector 1, m -> m/0'
+	key, _ := hex.DecodeString("e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35")
+	chainCode, _ := hex.DecodeString("873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508")
+	childKey, childChainCode, err := deriveChild(key, chainCode, 0x80000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(childKey), "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"; got != want {
+		t.Fatalf("got key %s, want %s", got, want)
+	}
+	if got, want := hex.EncodeToString(childChainCode), "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"; got != want {
+		t.Fatalf("got chain code %s, want %s", got, want)
+	}
+}
+
+func TestMnemonicToSeed(t *testing.T) {
+	// BIP-39 reference vector with the passphrase TREZOR; case and spacing do not matter
+	for _, mnemonic := range []string{testMnemonic, "  Abandon abandon ABANDON abandon abandon abandon\tabandon abandon abandon abandon abandon about\n"} {
+		seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+		if got := hex.EncodeToString(seed); got != want {
+			t.Fatalf("got seed %s, want %s", got, want)
+		}
+	}
+
+	for _, mnemonic := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", // Bad checksum
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abou",    // Not a word
+		"abandon about", // Too short
+	} {
+		if _, err := MnemonicToSeed(mnemonic, ""); !errors.Is(err, ErrInvalidMnemonic) {
+			t.Errorf("%q: got %v, want ErrInvalidMnemonic", mnemonic, err)
+		}
+	}
+}
+
+// BIP-44 Ethereum accounts of the "abandon ... about" mnemonic, as shown by common wallets
+func TestDeriveAddresses(t *testing.T) {
+	for _, tc := range []struct {
+		from uint32
+		want []string
+	}{
+		{0, []string{"0x9858EfFD232B4033E47d90003D41EC34EcaEda94", "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"}},
+		{1, []string{"0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"}},
+	} {
+		addresses, err := DeriveAddresses(testMnemonic, "", tc.from, uint32(len(tc.want)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range tc.want {
+			if addresses[i] != common.HexToAddress(want) {
+				t.Errorf("account %d: got %s, want %s", tc.from+uint32(i), addresses[i].Hex(), want)
+			}
+		}
+	}
+
+	// The BIP-39 passphrase selects a different wallet
+	addresses, err := DeriveAddresses(testMnemonic, "TREZOR", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addresses[0] == common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94") {
+		t.Fatal("passphrase did not change the derived account")
+	}
+}
+
+// Every derived account is a scrypt import, so a huge count is refused before any work is done
+func TestRecoverRejectsTooManyAccounts(t *testing.T) {
+	dir := t.TempDir()
+	hd := NewHDStore(dir, NewStore(dir))
+	if _, err := hd.Recover(testMnemonic, "", "secret", MaxHDAccounts+1); !errors.Is(err, ErrTooManyAccounts) {
+		t.Fatalf("got %v, want %v", err, ErrTooManyAccounts)
+	}
+	if _, err := hd.Recover(testMnemonic, "", "secret", 4000000000); !errors.Is(err, ErrTooManyAccounts) {
+		t.Fatalf("got %v, want %v", err, ErrTooManyAccounts)
+	}
+}
@@ -0,0 +1,241 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// MaxHDAccounts is the most accounts one Create or Recover derives. Each one is a scrypt keystore
+// import, about a second and 256 MB, made while the store is locked.
+const MaxHDAccounts = 100
+
+var (
+	ErrHDWalletNotFound = errors.New("hd wallet not found")
+	ErrTooManyAccounts  = fmt.Errorf("at most %d accounts can be derived at once", MaxHDAccounts)
+)
+
+// HDWallet is a BIP-44 account tree kept server side. The mnemonic is stored encrypted with the
+// same scheme as keystore files; derived accounts are imported into the keystore as normal keys.
+type HDWallet struct {
+	ID        string              `json:"id"`        // Address of account 0, stable across recoveries
+	NextIndex uint32              `json:"nextIndex"` // Index the next DeriveNext call will use
+	Accounts  []string            `json:"accounts"`  // Derived addresses, position = account index
+	Crypto    keystore.CryptoJSON `json:"crypto"`    // Encrypted mnemonic + BIP-39 passphrase
+}
+
+// hdSecret is the plaintext encrypted into HDWallet.Crypto
+type hdSecret struct {
+	Mnemonic   string `json:"mnemonic"`
+	Passphrase string `json:"passphrase"`
+}
+
+// HDStore manages HD wallets, one JSON file per wallet in dir, with their accounts in a Store
+type HDStore struct {
+	dir  string
+	keys *Store
+	mu   sync.Mutex // Serializes index allocation
+}
+
+// NewHDStore opens the HD wallet directory; derived keys go into keys
+func NewHDStore(dir string, keys *Store) *HDStore {
+	return &HDStore{dir: dir, keys: keys}
+}
+
+// Create generates a mnemonic with the given entropy bits and derives account 0 (plus count-1 more).
+// The mnemonic is returned once for the paper backup and otherwise only kept encrypted with passphrase.
+func (s *HDStore) Create(bits int, mnemonicPassphrase, passphrase string, count uint32) (string, *HDWallet, error) {
+	mnemonic, err := NewMnemonic(bits)
+	if err != nil {
+		return "", nil, err
+	}
+	w, err := s.Recover(mnemonic, mnemonicPassphrase, passphrase, count)
+	if err != nil {
+		return "", nil, err
+	}
+	return mnemonic, w, nil
+}
+
+// Recover rebuilds an HD wallet from its mnemonic and derives accounts 0..count-1 into the keystore.
+// Recovering a wallet that already exists keeps its accounts and only adds missing ones. count is at
+// most MaxHDAccounts.
+func (s *HDStore) Recover(mnemonic, mnemonicPassphrase, passphrase string, count uint32) (*HDWallet, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	if count == 0 {
+		count = 1
+	}
+	if count > MaxHDAccounts {
+		return nil, fmt.Errorf("%w, asked for %d", ErrTooManyAccounts, count)
+	}
+	mnemonic = normalizeMnemonic(mnemonic)
+	seed, err := MnemonicToSeed(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, _ := json.Marshal(hdSecret{Mnemonic: mnemonic, Passphrase: mnemonicPassphrase})
+	encrypted, err := keystore.EncryptDataV3(secret, []byte(passphrase), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := &HDWallet{Crypto: encrypted}
+	for i := uint32(0); i < count; i++ {
+		key, err := s.importAccount(seed, i, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		w.Accounts = append(w.Accounts, key.Address)
+	}
+	w.ID = strings.ToLower(w.Accounts[0])
+	w.NextIndex = count
+
+	if existing, err := s.load(w.ID); err == nil && existing.NextIndex > w.NextIndex {
+		w.NextIndex = existing.NextIndex // Never hand out an index twice
+		w.Accounts = existing.Accounts
+	}
+	if err := s.save(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// DeriveNext derives the next unused account of wallet id and imports it into the keystore
+func (s *HDStore) DeriveNext(id, passphrase string) (*WalletKey, uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, err := s.load(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	seed, err := s.seed(w, passphrase)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index := w.NextIndex
+	key, err := s.importAccount(seed, index, passphrase)
+	if err != nil {
+		return nil, 0, err
+	}
+	w.Accounts = append(w.Accounts, key.Address)
+	w.NextIndex++
+	if err := s.save(w); err != nil {
+		return nil, 0, err
+	}
+	return key, index, nil
+}
+
+// Get returns the stored description of wallet id
+func (s *HDStore) Get(id string) (*HDWallet, error) {
+	return s.load(id)
+}
+
+// List returns all HD wallets
+func (s *HDStore) List() ([]*HDWallet, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var list []*HDWallet
+	for _, f := range files {
+		w, err := s.load(strings.TrimSuffix(filepath.Base(f), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+// Mnemonic decrypts the mnemonic of wallet id, e.g. for a new paper backup or a share split
+func (s *HDStore) Mnemonic(id, passphrase string) (string, error) {
+	w, err := s.load(id)
+	if err != nil {
+		return "", err
+	}
+	secret, err := s.decrypt(w, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return secret.Mnemonic, nil
+}
+
+// importAccount derives account index and stores it encrypted with passphrase, tolerating keys already present
+func (s *HDStore) importAccount(seed []byte, index uint32, passphrase string) (*WalletKey, error) {
+	privateKey, err := DeriveAccount(seed, index)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.keys.ks.ImportECDSA(privateKey, passphrase); err != nil && !errors.Is(err, ErrAccountExists) {
+		return nil, fmt.Errorf("failed to store account %d: %w", index, err)
+	}
+	return newWalletKey(&privateKey.PublicKey), nil
+}
+
+// seed decrypts the mnemonic of w and stretches it into the BIP-39 seed
+func (s *HDStore) seed(w *HDWallet, passphrase string) ([]byte, error) {
+	secret, err := s.decrypt(w, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return MnemonicToSeed(secret.Mnemonic, secret.Passphrase)
+}
+
+func (s *HDStore) decrypt(w *HDWallet, passphrase string) (*hdSecret, error) {
+	plain, err := keystore.DecryptDataV3(w.Crypto, passphrase)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	var secret hdSecret
+	if err := json.Unmarshal(plain, &secret); err != nil {
+		return nil, fmt.Errorf("corrupt hd wallet %s: %w", w.ID, err)
+	}
+	return &secret, nil
+}
+
+func (s *HDStore) load(id string) (*HDWallet, error) {
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrHDWalletNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var w HDWallet
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("corrupt hd wallet %s: %w", id, err)
+	}
+	return &w, nil
+}
+
+func (s *HDStore) save(w *HDWallet) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path(w.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(w.ID)) // Atomic replace so a crash never leaves half a file
+}
+
+func (s *HDStore) path(id string) string {
+	return filepath.Join(s.dir, strings.ToLower(filepath.Base(id))+".json")
+}
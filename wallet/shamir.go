@@ -0,0 +1,266 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Kinds of secret a share set can hold
+const (
+	ShareKindKey      = "key"      // 32 byte private key
+	ShareKindMnemonic = "mnemonic" // BIP-39 entropy; account 0 is derived for verification
+)
+
+// sharePrefix starts every encoded share, so a share is recognisable on paper
+const sharePrefix = "msw-share1-"
+
+const (
+	shareVersion    = 1
+	shareHeaderSize = 1 + 4 + 4 + 1 + 1 + 1 + 1 + common.AddressLength // version, set id, created, threshold, total, index, kind, address
+	shareCheckSize  = 4                                                // Truncated SHA-256 over everything before it
+)
+
+var (
+	ErrInvalidShare       = errors.New("invalid share")
+	ErrShareChecksum      = errors.New("share checksum mismatch, check for typos")
+	ErrShareMismatch      = errors.New("shares belong to different backups")
+	ErrDuplicateShare     = errors.New("duplicate share")
+	ErrNotEnoughShares    = errors.New("not enough shares")
+	ErrRecoveredWrongAddr = errors.New("recovered secret does not match the expected address")
+)
+
+// Share is one part of a Shamir secret sharing backup. Any Threshold shares of the same set
+// recover the secret; fewer reveal nothing about it.
+type Share struct {
+	SetID     uint32         // Random id common to all shares of one split
+	Created   time.Time      // When the split was made
+	Threshold int            // Shares needed for recovery (M)
+	Total     int            // Shares handed out (N)
+	Index     int            // x coordinate of this share, 1..Total
+	Kind      string         // ShareKindKey or ShareKindMnemonic
+	Address   common.Address // Address the recovered secret must produce
+	Value     []byte         // y coordinates, one per secret byte
+}
+
+// SplitKey splits a private key into total shares, any threshold of which recover it
+func SplitKey(key *ecdsa.PrivateKey, threshold, total int) ([]Share, error) {
+	return split(ShareKindKey, crypto.FromECDSA(key), crypto.PubkeyToAddress(key.PublicKey), threshold, total)
+}
+
+// SplitMnemonic splits a BIP-39 mnemonic into total shares, any threshold of which recover it.
+// The shares record account 0 derived with mnemonicPassphrase, which recovery checks against.
+func SplitMnemonic(mnemonic, mnemonicPassphrase string, threshold, total int) ([]Share, error) {
+	mnemonic = normalizeMnemonic(mnemonic)
+	addresses, err := DeriveAddresses(mnemonic, mnemonicPassphrase, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	entropy, err := bip39.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	return split(ShareKindMnemonic, entropy, addresses[0], threshold, total)
+}
+
+// CombineKey recovers a private key from shares and checks it controls the address they record
+func CombineKey(shares []Share) (*ecdsa.PrivateKey, error) {
+	secret, err := combine(shares, ShareKindKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ToECDSA(secret)
+	if err != nil || crypto.PubkeyToAddress(key.PublicKey) != shares[0].Address {
+		return nil, ErrRecoveredWrongAddr
+	}
+	return key, nil
+}
+
+// CombineMnemonic recovers a mnemonic from shares and checks that account 0, derived with
+// mnemonicPassphrase, is the address they record
+func CombineMnemonic(shares []Share, mnemonicPassphrase string) (string, error) {
+	secret, err := combine(shares, ShareKindMnemonic)
+	if err != nil {
+		return "", err
+	}
+	mnemonic, err := bip39.NewMnemonic(secret)
+	if err != nil {
+		return "", ErrRecoveredWrongAddr
+	}
+	addresses, err := DeriveAddresses(mnemonic, mnemonicPassphrase, 0, 1)
+	if err != nil || addresses[0] != shares[0].Address {
+		return "", ErrRecoveredWrongAddr
+	}
+	return mnemonic, nil
+}
+
+// String encodes the share as prefixed hex with a trailing checksum
+func (s Share) String() string {
+	buf := make([]byte, shareHeaderSize, shareHeaderSize+len(s.Value)+shareCheckSize)
+	buf[0] = shareVersion
+	binary.BigEndian.PutUint32(buf[1:5], s.SetID)
+	binary.BigEndian.PutUint32(buf[5:9], uint32(s.Created.Unix()))
+	buf[9], buf[10], buf[11] = byte(s.Threshold), byte(s.Total), byte(s.Index)
+	if s.Kind == ShareKindMnemonic {
+		buf[12] = 1
+	}
+	copy(buf[13:], s.Address.Bytes())
+	buf = append(buf, s.Value...)
+	sum := sha256.Sum256(buf)
+	return sharePrefix + hex.EncodeToString(append(buf, sum[:shareCheckSize]...))
+}
+
+// ParseShare decodes a share produced by Share.String, rejecting it if the checksum does not match
+func ParseShare(encoded string) (Share, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(encoded), sharePrefix))
+	if err != nil || len(raw) <= shareHeaderSize+shareCheckSize {
+		return Share{}, ErrInvalidShare
+	}
+	body, check := raw[:len(raw)-shareCheckSize], raw[len(raw)-shareCheckSize:]
+	if sum := sha256.Sum256(body); !bytes.Equal(sum[:shareCheckSize], check) {
+		return Share{}, ErrShareChecksum
+	}
+	if body[0] != shareVersion {
+		return Share{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidShare, body[0])
+	}
+
+	s := Share{
+		SetID:     binary.BigEndian.Uint32(body[1:5]),
+		Created:   time.Unix(int64(binary.BigEndian.Uint32(body[5:9])), 0).UTC(),
+		Threshold: int(body[9]),
+		Total:     int(body[10]),
+		Index:     int(body[11]),
+		Kind:      ShareKindKey,
+		Address:   common.BytesToAddress(body[13:shareHeaderSize]),
+		Value:     body[shareHeaderSize:],
+	}
+	if body[12] == 1 {
+		s.Kind = ShareKindMnemonic
+	}
+	if s.Index < 1 || s.Index > s.Total || s.Threshold < 2 || s.Threshold > s.Total {
+		return Share{}, ErrInvalidShare
+	}
+	return s, nil
+}
+
+// split builds the shares of secret: each byte is the constant term of a random polynomial
+// of degree threshold-1 over GF(2^8), and share i holds the polynomials evaluated at x = i.
+func split(kind string, secret []byte, address common.Address, threshold, total int) ([]Share, error) {
+	if threshold < 2 || threshold > total || total > 255 {
+		return nil, fmt.Errorf("need 2 <= threshold <= shares <= 255, got threshold %d of %d", threshold, total)
+	}
+	var id [4]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, total)
+	for i := range shares {
+		shares[i] = Share{
+			SetID:     binary.BigEndian.Uint32(id[:]),
+			Created:   time.Now().UTC().Truncate(time.Second),
+			Threshold: threshold,
+			Total:     total,
+			Index:     i + 1,
+			Kind:      kind,
+			Address:   address,
+			Value:     make([]byte, len(secret)),
+		}
+	}
+
+	coefficients := make([]byte, threshold)
+	for b, secretByte := range secret {
+		coefficients[0] = secretByte
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for i := range shares {
+			shares[i].Value[b] = gfEval(coefficients, byte(shares[i].Index))
+		}
+	}
+	for i := range coefficients {
+		coefficients[i] = 0
+	}
+	return shares, nil
+}
+
+// combine checks that shares form a complete set of kind and interpolates the secret at x = 0
+func combine(shares []Share, kind string) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	first := shares[0]
+	if first.Kind != kind {
+		return nil, fmt.Errorf("%w: shares hold a %s, not a %s", ErrShareMismatch, first.Kind, kind)
+	}
+	seen := map[int]bool{}
+	for _, s := range shares {
+		if s.SetID != first.SetID || s.Threshold != first.Threshold || s.Kind != first.Kind ||
+			s.Address != first.Address || len(s.Value) != len(first.Value) {
+			return nil, ErrShareMismatch
+		}
+		if seen[s.Index] {
+			return nil, fmt.Errorf("%w: index %d", ErrDuplicateShare, s.Index)
+		}
+		seen[s.Index] = true
+	}
+	if len(shares) < first.Threshold {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrNotEnoughShares, len(shares), first.Threshold)
+	}
+
+	used := shares[:first.Threshold]
+	secret := make([]byte, len(first.Value))
+	for i, si := range used {
+		// Lagrange basis polynomial of share i evaluated at 0: prod x_j / (x_j - x_i), subtraction is xor
+		basis := byte(1)
+		for j, sj := range used {
+			if i != j {
+				basis = gfMul(basis, gfDiv(byte(sj.Index), byte(sj.Index)^byte(si.Index)))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(si.Value[b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// gfEval evaluates the polynomial with the given coefficients (constant term first) at x
+func gfEval(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// gfMul multiplies in GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1, without branching on secrets
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// gfDiv divides a by b (b != 0) using b^254 = b^-1
+func gfDiv(a, b byte) byte {
+	inverse := byte(1)
+	for i := 0; i < 254; i++ {
+		inverse = gfMul(inverse, b)
+	}
+	return gfMul(a, inverse)
+}
@@ -0,0 +1,201 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// subsets returns every k-element subset of shares, keeping their order
+func subsets(shares []Share, k int) [][]Share {
+	if k == 0 {
+		return [][]Share{nil}
+	}
+	var out [][]Share
+	for i := 0; i+k <= len(shares); i++ {
+		for _, rest := range subsets(shares[i+1:], k-1) {
+			out = append(out, append([]Share{shares[i]}, rest...))
+		}
+	}
+	return out
+}
+
+func TestSplitCombineKey(t *testing.T) {
+	for _, tc := range []struct {
+		threshold, total int
+	}{
+		{2, 2}, {2, 3}, {3, 5}, {5, 5}, {4, 7},
+	} {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares, err := SplitKey(key, tc.threshold, tc.total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(shares) != tc.total {
+			t.Fatalf("%d of %d: got %d shares", tc.threshold, tc.total, len(shares))
+		}
+
+		// Every threshold-sized subset recovers the key, in any order
+		for _, subset := range subsets(shares, tc.threshold) {
+			for _, ordered := range [][]Share{subset, reversed(subset)} {
+				recovered, err := CombineKey(ordered)
+				if err != nil {
+					t.Fatalf("%d of %d: %v", tc.threshold, tc.total, err)
+				}
+				if !bytes.Equal(crypto.FromECDSA(recovered), crypto.FromECDSA(key)) {
+					t.Fatalf("%d of %d: shares %v recovered the wrong key", tc.threshold, tc.total, indexes(ordered))
+				}
+			}
+		}
+
+		// Fewer shares are refused
+		for _, subset := range subsets(shares, tc.threshold-1) {
+			if _, err := CombineKey(subset); !errors.Is(err, ErrNotEnoughShares) {
+				t.Fatalf("%d of %d: %d shares gave %v, want ErrNotEnoughShares", tc.threshold, tc.total, len(subset), err)
+			}
+		}
+	}
+}
+
+func TestSplitCombineMnemonic(t *testing.T) {
+	shares, err := SplitMnemonic(testMnemonic, "TREZOR", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, subset := range subsets(shares, 2) {
+		mnemonic, err := CombineMnemonic(subset, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != testMnemonic {
+			t.Fatalf("shares %v recovered %q", indexes(subset), mnemonic)
+		}
+	}
+
+	if _, err := CombineMnemonic(shares[:2], ""); !errors.Is(err, ErrRecoveredWrongAddr) {
+		t.Fatalf("wrong passphrase gave %v, want ErrRecoveredWrongAddr", err)
+	}
+	if _, err := CombineKey(shares[:2]); !errors.Is(err, ErrShareMismatch) {
+		t.Fatalf("mnemonic shares combined as a key gave %v, want ErrShareMismatch", err)
+	}
+}
+
+func TestCombineRejectsBadSets(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := SplitKey(key, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := SplitKey(key, 2, 3) // Same key, different split
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := shares[1]
+	tampered.Value = append([]byte(nil), tampered.Value...)
+	tampered.Value[0] ^= 1
+
+	for _, tc := range []struct {
+		name   string
+		shares []Share
+		want   error
+	}{
+		{"none", nil, ErrNotEnoughShares},
+		{"one", shares[:1], ErrNotEnoughShares},
+		{"mixed sets", []Share{shares[0], other[1]}, ErrShareMismatch},
+		{"duplicate", []Share{shares[0], shares[0]}, ErrDuplicateShare},
+		{"tampered value", []Share{shares[0], tampered}, ErrRecoveredWrongAddr},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := CombineKey(tc.shares); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitRejectsBadThresholds(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		threshold, total int
+	}{
+		{1, 3}, {4, 3}, {0, 0}, {2, 256},
+	} {
+		if _, err := SplitKey(key, tc.threshold, tc.total); err == nil {
+			t.Errorf("%d of %d: split succeeded", tc.threshold, tc.total)
+		}
+	}
+}
+
+func TestParseShare(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := SplitKey(key, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := shares[2].String()
+
+	parsed, err := ParseShare("  " + encoded + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.SetID != shares[2].SetID || parsed.Index != 3 || parsed.Threshold != 2 || parsed.Total != 3 ||
+		parsed.Kind != ShareKindKey || parsed.Address != shares[2].Address || !parsed.Created.Equal(shares[2].Created) ||
+		!bytes.Equal(parsed.Value, shares[2].Value) {
+		t.Fatalf("round trip changed the share: %+v", parsed)
+	}
+
+	// A typo in any hex digit is caught by the checksum
+	typo := []byte(encoded)
+	last := len(typo) - 10
+	if typo[last] == '0' {
+		typo[last] = '1'
+	} else {
+		typo[last] = '0'
+	}
+	for _, tc := range []struct {
+		name    string
+		encoded string
+		want    error
+	}{
+		{"typo", string(typo), ErrShareChecksum},
+		{"truncated", encoded[:len(encoded)-2], ErrShareChecksum},
+		{"not hex", encoded + "zz", ErrInvalidShare},
+		{"empty", sharePrefix, ErrInvalidShare},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseShare(tc.encoded); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func reversed(shares []Share) []Share {
+	out := make([]Share, len(shares))
+	for i, s := range shares {
+		out[len(shares)-1-i] = s
+	}
+	return out
+}
+
+func indexes(shares []Share) []int {
+	var out []int
+	for _, s := range shares {
+		out = append(out, s.Index)
+	}
+	return out
+}
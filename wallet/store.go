@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"          // Account type shared by all go-ethereum backends
+	"github.com/ethereum/go-ethereum/accounts/keystore" // Web3 Secret Storage (v3 JSON keystore) implementation
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrAccountNotFound = keystore.ErrNoMatch              // No key file for the address
+	ErrWrongPassphrase = keystore.ErrDecrypt              // Passphrase does not decrypt the key file
+	ErrAccountExists   = keystore.ErrAccountAlreadyExists // Import of an address that is already stored
+	ErrLocked          = keystore.ErrLocked               // Signing with an account that is not unlocked
+	ErrEmptyPassphrase = errors.New("passphrase is required")
+)
+
+// Account is a stored key as seen by callers: its address and the key file holding it
+type Account struct {
+	Address string `json:"address"`
+	File    string `json:"file"`
+}
+
+// Store keeps wallet keys encrypted on disk as v3 JSON keystore files.
+// Keys are only decrypted for signing, either per call or while an account is unlocked.
+type Store struct {
+	ks *keystore.KeyStore
+}
+
+// NewStore opens (or creates) the keystore directory using the standard scrypt parameters
+func NewStore(dir string) *Store {
+	return &Store{ks: keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)}
+}
+
+// Create generates a new key, stores it encrypted with passphrase and returns its public description
+func (s *Store) Create(passphrase string) (*WalletKey, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	privateKey, key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ks.ImportECDSA(privateKey, passphrase); err != nil {
+		return nil, fmt.Errorf("failed to store key: %w", err)
+	}
+	return key, nil
+}
+
+// List returns every account in the keystore
+func (s *Store) List() []Account {
+	var list []Account
+	for _, a := range s.ks.Accounts() {
+		list = append(list, Account{Address: a.Address.Hex(), File: a.URL.Path})
+	}
+	return list
+}
+
+// Has reports whether the keystore holds a key for address
+func (s *Store) Has(address common.Address) bool {
+	return s.ks.HasAddress(address)
+}
+
+// Import stores a v3 key file, re-encrypting it from passphrase to newPassphrase
+func (s *Store) Import(keyJSON []byte, passphrase, newPassphrase string) (common.Address, error) {
+	if newPassphrase == "" {
+		return common.Address{}, ErrEmptyPassphrase
+	}
+	account, err := s.ks.Import(keyJSON, passphrase, newPassphrase)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// ImportPrivateKey stores a raw hex private key encrypted with passphrase
+func (s *Store) ImportPrivateKey(privateKeyHex, passphrase string) (common.Address, error) {
+	if passphrase == "" {
+		return common.Address{}, ErrEmptyPassphrase
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid private key: %w", err)
+	}
+	account, err := s.ks.ImportECDSA(privateKey, passphrase)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// Export returns the key file of address re-encrypted with newPassphrase, for backup or moving it elsewhere
+func (s *Store) Export(address common.Address, passphrase, newPassphrase string) ([]byte, error) {
+	if newPassphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	return s.ks.Export(accounts.Account{Address: address}, passphrase, newPassphrase)
+}
+
+// Delete removes the key file of address; the passphrase must match
+func (s *Store) Delete(address common.Address, passphrase string) error {
+	return s.ks.Delete(accounts.Account{Address: address}, passphrase)
+}
+
+// Unlock decrypts the key of address and keeps it in memory for timeout (0 keeps it until Lock)
+func (s *Store) Unlock(address common.Address, passphrase string, timeout time.Duration) error {
+	return s.ks.TimedUnlock(accounts.Account{Address: address}, passphrase, timeout)
+}
+
+// Lock removes the decrypted key of address from memory
+func (s *Store) Lock(address common.Address) error {
+	return s.ks.Lock(address)
+}
+
+// SignTx signs tx with the unlocked key of address
+func (s *Store) SignTx(address common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTx(accounts.Account{Address: address}, tx, chainID)
+}
+
+// SignHash signs a 32 byte hash with the unlocked key of address, returning [R || S || V] with V 0 or 1
+func (s *Store) SignHash(address common.Address, hash []byte) ([]byte, error) {
+	return s.ks.SignHash(accounts.Account{Address: address}, hash)
+}
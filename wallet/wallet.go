@@ -8,34 +8,28 @@ import (
 	"github.com/ethereum/go-ethereum/crypto" // Ethereum's cryptographic utility package
 )
 
-// WalletKey represents the structure of wallet with public and private keys of a wallet
+// WalletKey represents the public side of a wallet. The private key never leaves the keystore.
 type WalletKey struct {
-	Address    string `json:"address"`
-	PublicKey  string `json:"publicKey"` // Hex-encoded public key
-	PrivateKey string `json:"privateKey"`
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey"` // Hex-encoded public key
 }
 
-// GenerateKey creates a new Ethereum wallet key pair
-func GenerateKey() (*WalletKey, error) {
+// generateKey creates a new Ethereum key pair and returns it together with its public description
+func generateKey() (*ecdsa.PrivateKey, *WalletKey, error) {
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate private key: %v", err)
+		return nil, nil, fmt.Errorf("failed to generate private key: %v", err)
 	}
+	return privateKey, newWalletKey(&privateKey.PublicKey), nil
+}
 
-	privateKeyBytes := crypto.FromECDSA(privateKey)      // Convert private key to bytes
-	privateKeyHex := hex.EncodeToString(privateKeyBytes) // Encode bytes as hex string
-
-	publicKey := privateKey.Public().(*ecdsa.PublicKey) // Extract public key from private key
-	publicKeyBytes := crypto.FromECDSAPub(publicKey)    // Convert public key to bytes
-	publicKeyHex := hex.EncodeToString(publicKeyBytes)  // Encode public key as hex string
-
-	address := crypto.PubkeyToAddress(*publicKey).Hex()
-
-	// Return the WalletKey struct with address, public key, and private key
+// newWalletKey describes a public key by its address and hex encoding
+func newWalletKey(publicKey *ecdsa.PublicKey) *WalletKey {
+	publicKeyBytes := crypto.FromECDSAPub(publicKey)   // Convert public key to bytes
+	publicKeyHex := hex.EncodeToString(publicKeyBytes) // Encode public key as hex string
 
 	return &WalletKey{
-		Address:    address,
-		PublicKey:  publicKeyHex,
-		PrivateKey: privateKeyHex,
-	}, nil
+		Address:   crypto.PubkeyToAddress(*publicKey).Hex(),
+		PublicKey: publicKeyHex,
+	}
 }
@@ -0,0 +1,302 @@
+// walletctl is the command line companion of the API for key management that should not go over HTTP,
+// such as creating an HD wallet on an offline machine. It works on the same data directory as the server.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akarkareddy/ethereum-multisig-wallet/blockchain"
+	"github.com/akarkareddy/ethereum-multisig-wallet/config"
+	"github.com/akarkareddy/ethereum-multisig-wallet/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/term"
+)
+
+const usage = `usage: walletctl <command> [flags]
+
+commands:
+  mnemonic        print a new BIP-39 mnemonic (nothing is stored)
+  addresses       print the addresses derived from a mnemonic (nothing is stored)
+  hd-create       create an HD wallet in the keystore and print its mnemonic
+  hd-derive       derive the next account of an HD wallet
+  hd-recover      restore an HD wallet from a mnemonic, with -count accounts or those the chain shows in use
+  hd-list         list HD wallets
+  import-key      store a raw hex private key in the keystore
+  shamir-split    split a keystore key (-address) or an HD wallet mnemonic (-id) into -shares shares, -threshold of which recover it
+  shamir-recover  recover the key or HD wallet of -address from shares
+  shamir-inspect  check the checksum of shares and print their metadata
+
+The keystore passphrase is taken from the WALLETCTL_PASSPHRASE environment variable, or asked for on the
+terminal without it. The optional BIP-39 passphrase of a mnemonic comes from WALLETCTL_MNEMONIC_PASSPHRASE,
+or is asked for on the terminal (empty for none); without a terminal it is empty. Mnemonics are read from -mnemonic-file and private keys from -key-file, or from standard
+input without them. Shares are read from the files given as arguments, or one per line from standard input
+without them. None of these secrets are taken from the command line.`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	configPath := fs.String("config", config.Path(), "path to the JSON config file")
+	mnemonicFile := fs.String("mnemonic-file", "", "file holding the BIP-39 mnemonic (default: read it from standard input)")
+	keyFile := fs.String("key-file", "", "import-key: file holding the hex private key (default: read it from standard input)")
+	words := fs.Int("words", 12, "mnemonic length: 12, 15, 18, 21 or 24")
+	id := fs.String("id", "", "HD wallet id (address of account 0)")
+	count := fs.Uint("count", 1, "number of accounts (hd-recover: without it, the accounts the chain shows in use)")
+	address := fs.String("address", "", "keystore account to split, or the address recovery must produce")
+	threshold := fs.Int("threshold", 2, "shares needed to recover the secret")
+	total := fs.Int("shares", 3, "number of shares to create")
+	outDir := fs.String("out", "", "shamir-split: write each share to its own file in this directory")
+	fs.Parse(args)
+
+	cfg, err := config.Read(*configPath) // Key management works offline, an incomplete network section does not matter
+	if err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	config.Set(cfg)
+	keys := wallet.NewStore(cfg.KeystoreDir())
+	hdWallets := wallet.NewHDStore(cfg.HDDir(), keys)
+
+	switch cmd {
+	case "mnemonic":
+		m, err := wallet.NewMnemonic(*words / 3 * 32)
+		check(err)
+		fmt.Println(m)
+
+	case "addresses":
+		addresses, err := wallet.DeriveAddresses(readSecret(*mnemonicFile, "Mnemonic"), readMnemonicPassphrase(false), 0, uint32(*count))
+		check(err)
+		for i, a := range addresses {
+			fmt.Printf("%s\t%s\n", wallet.AccountPath(uint32(i)), a.Hex())
+		}
+
+	case "hd-create":
+		m, hd, err := hdWallets.Create(*words/3*32, readMnemonicPassphrase(true), readPassphrase(true), uint32(*count))
+		check(err)
+		fmt.Println("id:      ", hd.ID)
+		fmt.Println("mnemonic:", m)
+		fmt.Println("Write the mnemonic down and keep it offline; it is the only backup of every account.")
+		printAccounts(hd)
+
+	case "hd-derive":
+		key, index, err := hdWallets.DeriveNext(*id, readPassphrase(false))
+		check(err)
+		fmt.Printf("%s\t%s\n", wallet.AccountPath(index), key.Address)
+
+	case "hd-recover":
+		m := readSecret(*mnemonicFile, "Mnemonic")
+		check(wallet.ValidateMnemonic(m))
+		mnemonicPassphrase := readMnemonicPassphrase(false)
+		n := uint32(*count)
+		if !countSet(fs) {
+			n, err = blockchain.HDAccountsInUse(m, mnemonicPassphrase)
+			if err != nil {
+				log.Fatalf("scanning the chain for used accounts: %v (give -count to recover offline)", err)
+			}
+		}
+		hd, err := hdWallets.Recover(m, mnemonicPassphrase, readPassphrase(true), n)
+		check(err)
+		fmt.Println("id:", hd.ID)
+		printAccounts(hd)
+
+	case "hd-list":
+		list, err := hdWallets.List()
+		check(err)
+		for _, hd := range list {
+			fmt.Printf("%s\t%d accounts\n", hd.ID, len(hd.Accounts))
+		}
+
+	case "import-key":
+		imported, err := keys.ImportPrivateKey(readSecret(*keyFile, "Private key"), readPassphrase(true))
+		check(err)
+		fmt.Println("imported:", imported.Hex())
+
+	case "shamir-split":
+		var shares []wallet.Share
+		if *id != "" {
+			shares, err = hdWallets.SplitMnemonic(*id, readPassphrase(false), *threshold, *total)
+		} else {
+			shares, err = keys.SplitKey(parseAddress(*address), readPassphrase(false), *threshold, *total)
+		}
+		check(err)
+		for _, share := range shares {
+			if *outDir == "" {
+				fmt.Println(share)
+				continue
+			}
+			check(os.MkdirAll(*outDir, 0700))
+			file := filepath.Join(*outDir, fmt.Sprintf("share-%08x-%d.txt", share.SetID, share.Index))
+			check(os.WriteFile(file, []byte(share.String()+"\n"), 0600))
+			fmt.Println(file)
+		}
+		fmt.Printf("Hand each of the %d shares to a different custodian; any %d of them recover %s.\n", *total, *threshold, shares[0].Address.Hex())
+
+	case "shamir-recover":
+		expected := parseAddress(*address)
+		shares := readShares(fs.Args())
+		passphrase := readPassphrase(true)
+		if shares[0].Kind == wallet.ShareKindMnemonic {
+			hd, err := hdWallets.RecoverShares(shares, expected, readMnemonicPassphrase(false), passphrase, uint32(*count))
+			check(err)
+			fmt.Println("id:", hd.ID)
+			printAccounts(hd)
+		} else {
+			recovered, err := keys.RecoverKey(shares, expected, passphrase)
+			check(err)
+			fmt.Println("recovered:", recovered.Hex())
+		}
+
+	case "shamir-inspect":
+		for _, share := range readShares(fs.Args()) {
+			fmt.Printf("set %08x\tshare %d of %d (threshold %d)\t%s of %s\tcreated %s\n",
+				share.SetID, share.Index, share.Total, share.Threshold, share.Kind, share.Address.Hex(), share.Created.Format(time.RFC3339))
+		}
+
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// readSecret reads a mnemonic or private key from file, or from standard input when file is empty, so it
+// never shows up in the shell history or the process list. label names it in the prompt.
+func readSecret(file, label string) string {
+	var data []byte
+	var err error
+	if file != "" {
+		data, err = os.ReadFile(file)
+	} else {
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprint(os.Stderr, label+": ") // Typed in rather than piped
+		}
+		data, err = bufio.NewReader(os.Stdin).ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(data) > 0 {
+			err = nil // Piped in without a final newline
+		}
+	}
+	check(err)
+	return strings.Join(strings.Fields(string(data)), " ")
+}
+
+// readPassphrase returns the keystore passphrase from WALLETCTL_PASSPHRASE, or asks for it on the terminal
+// without echoing it. confirm asks twice, for a passphrase that is about to protect a new key.
+func readPassphrase(confirm bool) string {
+	if passphrase, ok := os.LookupEnv("WALLETCTL_PASSPHRASE"); ok {
+		return passphrase
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		log.Fatal("standard input is not a terminal: set WALLETCTL_PASSPHRASE to give the keystore passphrase")
+	}
+	passphrase := prompt(fd, "Passphrase: ")
+	if confirm && prompt(fd, "Repeat passphrase: ") != passphrase {
+		log.Fatal("passphrases do not match")
+	}
+	return passphrase
+}
+
+// readMnemonicPassphrase returns the BIP-39 passphrase from WALLETCTL_MNEMONIC_PASSPHRASE, or asks for it on
+// the terminal without echoing it. Without either there is none. confirm asks twice, for a new mnemonic.
+func readMnemonicPassphrase(confirm bool) string {
+	if passphrase, ok := os.LookupEnv("WALLETCTL_MNEMONIC_PASSPHRASE"); ok {
+		return passphrase
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return ""
+	}
+	passphrase := prompt(fd, "BIP-39 passphrase (empty for none): ")
+	if confirm && passphrase != "" && prompt(fd, "Repeat BIP-39 passphrase: ") != passphrase {
+		log.Fatal("passphrases do not match")
+	}
+	return passphrase
+}
+
+func prompt(fd int, label string) string {
+	fmt.Fprint(os.Stderr, label)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	check(err)
+	return string(passphrase)
+}
+
+// countSet reports whether -count was given
+func countSet(fs *flag.FlagSet) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) { set = set || f.Name == "count" })
+	return set
+}
+
+// readShares parses the share held in each file named in args, or one share per line of standard input
+// when there are none. Typed in, an empty line ends the list.
+func readShares(files []string) []wallet.Share {
+	var shares []wallet.Share
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			// Without the path: a share pasted as an argument by mistake is not echoed back
+			log.Fatalf("argument %d is not a readable share file: %v", i+1, errors.Unwrap(err))
+		}
+		share, err := wallet.ParseShare(string(data))
+		if err != nil {
+			log.Fatalf("%s: %v", file, err)
+		}
+		shares = append(shares, share)
+	}
+	if len(files) == 0 {
+		interactive := term.IsTerminal(int(os.Stdin.Fd()))
+		if interactive {
+			fmt.Fprintln(os.Stderr, "Shares, one per line (empty line to finish):")
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for line := 1; scanner.Scan(); line++ {
+			encoded := strings.TrimSpace(scanner.Text())
+			if encoded == "" {
+				if interactive {
+					break
+				}
+				continue
+			}
+			share, err := wallet.ParseShare(encoded)
+			if err != nil {
+				log.Fatalf("line %d: %v", line, err)
+			}
+			shares = append(shares, share)
+		}
+		check(scanner.Err())
+	}
+	if len(shares) == 0 {
+		log.Fatal("no shares given")
+	}
+	return shares
+}
+
+func parseAddress(address string) common.Address {
+	if !common.IsHexAddress(address) {
+		log.Fatal("-address must be an Ethereum address")
+	}
+	return common.HexToAddress(address)
+}
+
+func printAccounts(hd *wallet.HDWallet) {
+	for i, a := range hd.Accounts {
+		fmt.Printf("%s\t%s\n", wallet.AccountPath(uint32(i)), a)
+	}
+}
+
+func check(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}